@@ -6,10 +6,10 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
-
-	"github.com/aerogo/codetree"
-	"github.com/akyoto/color"
+	"unicode"
+	"unicode/utf8"
 )
 
 // DefaultCompiler is the default compiler used by the interface.
@@ -19,117 +19,406 @@ var DefaultCompiler = NewCompiler("components")
 type Compiler struct {
 	// PackageName contains the package name used in the generated .go files.
 	PackageName string
+
+	// AdaptiveSizeHints makes the generated functions adjust the initial buffer size
+	// to the moving average of the actual output sizes at runtime.
+	// Otherwise the buffer size is estimated at compile time.
+	AdaptiveSizeHints bool
+
+	// Target is the type of output that stream functions write to.
+	Target Target
+
+	// FlushAfterHead flushes the output after the end of the head element
+	// so that browsers can start loading assets while the body is rendered.
+	// It only has an effect on targets that can be flushed.
+	FlushAfterHead bool
+
+	// Context adds a ctx context.Context parameter to every component which is passed on
+	// to the components it calls. It's the first parameter of both the normal functions
+	// and the stream functions, which take the writer after it. Templates can refer to it
+	// as ctx. The functions return an error and stop rendering when the context has been canceled.
+	Context bool
+
+	// ParallelLimit is the maximum number of components in a parallel block
+	// that are rendered at the same time. Zero means no limit.
+	ParallelLimit int
+
+	// Fragments generates a function for every component that renders only the subtree
+	// of an element with a constant id or of a fragment block, selected by its name.
+	Fragments bool
+
+	// Props generates a struct with a field for each parameter of a component
+	// and a function rendering the component with it. Calls with named arguments
+	// like Hello(person="World") use the struct, omitted arguments have their zero value.
+	Props bool
+
+	// ExportStreamFunctions names the stream functions StreamButton instead of streamButton
+	// so that templates in other packages can call the components. Templates import
+	// the package with a top-level import "example.com/app/ui" and call ui.Button("Save").
+	ExportStreamFunctions bool
+
+	// InlineThreshold is the maximum size in bytes of the generated code of a component
+	// that is inlined when it's called from a component in the same template.
+	InlineThreshold int
+
+	// Macros replace the elements with the given tag names by calls to components,
+	// like the top-level macro declarations of templates which take precedence.
+	Macros map[string]*Macro
+
+	// Transforms change the parsed components before the code is generated,
+	// e.g. to add attributes to all img elements. They are applied in order to every component.
+	Transforms []Transform
+
+	// filters contains the filters added with RegisterFilter.
+	filters map[string]func(string) (string, error)
 }
 
 // NewCompiler constructs a new Pixy compiler.
 func NewCompiler(packageName string) *Compiler {
 	return &Compiler{
-		PackageName: packageName,
+		PackageName:     packageName,
+		InlineThreshold: 512,
 	}
 }
 
 // Compile compiles a Pixy template as a string and returns a slice of components.
+// The code of go blocks is part of the code of the first component.
+// Included files are relative to the working directory.
 func (compiler *Compiler) Compile(reader io.Reader) ([]*Component, error) {
-	tree, err := codetree.New(reader)
+	return compiler.compile(reader, "")
+}
+
+// compile compiles a Pixy template read from the file.
+func (compiler *Compiler) compile(reader io.Reader, file string) ([]*Component, error) {
+	parsed, err := loadTemplate(reader, file)
 
 	if err != nil {
 		return nil, err
 	}
 
-	defer tree.Close()
-	components := []*Component{}
+	return compiler.compileTemplates([]*template{parsed})
+}
 
-	for _, node := range tree.Children {
-		// Ignore comments
-		if strings.HasPrefix(node.Line, "//") {
-			continue
+// compileTemplates compiles templates of the same package together,
+// so that calls know the components of the other templates.
+func (compiler *Compiler) compileTemplates(templates []*template) ([]*Component, error) {
+	var definitions []*Definition
+	var declarations []*Definition
+
+	for _, parsed := range templates {
+		if len(parsed.code) > 0 && len(parsed.definitions) == 0 {
+			return nil, errors.New("go blocks need a component in the same template")
 		}
 
-		// Disallow tags on the top level
-		if !strings.HasPrefix(node.Line, "component ") {
-			color.Yellow(node.Line)
-			color.Red("Only 'component' definitions are allowed on the top level.")
-			continue
+		definitions = append(definitions, parsed.definitions...)
+		declarations = append(declarations, parsed.declarations...)
+	}
+
+	err := ExpandMacros(definitions, compiler.Macros)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, definition := range definitions {
+		err := definition.Transform(compiler.Transforms...)
+
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	generator := newGenerator(compiler, definitions, declarations)
+	components := make([]*Component, 0, len(definitions))
+
+	for _, parsed := range templates {
+		for index, definition := range parsed.definitions {
+			component := compiler.compileComponent(generator, definition)
+
+			// The code of go blocks is part of the first component
+			if index == 0 {
+				for _, code := range parsed.code {
+					component.Code += "\n\n" + strings.TrimSuffix(code, "\n")
+				}
+			}
+
+			if compiler.Props && definition.Parameters != "" && definition.Receiver == "" {
+				component.Code += "\n\n" + compiler.compileProps(definition, generator.returnsError(definition.key()))
+			}
+
+			if compiler.Fragments {
+				fragments := generator.fragments(definition)
+
+				if fragments != nil {
+					component.Code += "\n\n" + strings.TrimPrefix(compiler.compileComponent(generator, fragments).Code, compiler.GetFileHeader())
+				}
+			}
+
+			// Packages imported by the template
+			imports := importDeclarations(component.Code, parsed.imports)
+
+			if imports != "" {
+				header := compiler.GetFileHeader()
+				component.Code = header + imports + "\n" + component.Code[len(header):]
+			}
+
+			components = append(components, component)
+		}
+	}
+
+	if generator.err != nil {
+		return nil, generator.err
+	}
+
+	return components, nil
+}
+
+// compileComponent generates the code for a single component.
+func (compiler *Compiler) compileComponent(generator *generator, definition *Definition) *Component {
+	componentName := definition.Name
+	target := targets[compiler.Target]
+	returnsError := generator.returnsError(definition.key())
+	parameters := definition.Parameters
+	receiver := ""
+
+	// Methods are declared with their receiver
+	if definition.Receiver != "" {
+		receiver = "(" + definition.Receiver + ") "
+	}
+
+	// Stream functions have the writer as the first parameter
+	streamParameters := target.parameter + ", " + parameters
+
+	// The context is passed on as the first parameter, even before the writer
+	if compiler.Context {
+		parameters = strings.TrimSuffix("ctx context.Context, "+parameters, ", ")
+		streamParameters = "ctx context.Context, " + streamParameters
+	}
+
+	typeParameters := typeArguments(definition.TypeParameters)
+	signature := receiver + componentName + typeParameters + "(" + parameters + ")"
+	parameterNames := extractParameterNames(definition.Parameters)
+
+	// Default values are inserted at the call site where the parameters aren't in scope
+	if definition.Defaults != nil {
+		compiler.checkDefaults(generator, definition)
+	}
+
+	// Buffered writers write to the buffer on release
+	writer := "_b"
+
+	if target.buffered {
+		writer = "_w"
+	}
+
+	if compiler.Context {
+		writer = "ctx, " + writer
+	}
+
+	// streamFunctionCall contains the function call for the streaming version.
+	// Type parameters are forwarded explicitly because they can't always be inferred.
+	streamFunctionCall := compiler.streamName(componentName)
+
+	if definition.Receiver != "" {
+		streamFunctionCall = strings.Fields(definition.Receiver)[0] + "." + streamFunctionCall
+	}
+
+	if definition.TypeParameters != "" {
+		streamFunctionCall += typeArguments(strings.Join(extractParameterNames(definition.TypeParameters), ", "))
+	}
+
+	streamFunctionCall += "(" + writer
+
+	if len(parameterNames) > 0 {
+		streamFunctionCall += ", " + strings.Join(parameterNames, ", ")
+	}
+
+	streamFunctionCall += ")"
+
+	// Return types of the normal function and the stream function
+	returnType := " string"
+	streamReturnType := ""
+	returnValues := ""
+
+	if returnsError {
+		returnType = " (string, error)"
+		streamReturnType = " error"
+		returnValues = ", nil"
+	}
+
+	// Generate a comment line so that the linter won't complain
+	comment := "// " + componentName + " component"
+
+	// Stream function body
+	generated := generator.component(definition)
+	streamFunctionBody := strings.Replace("\n"+generated.code, "\n", "\n\t", -1)
+	optimizedStreamFunctionBody, _ := optimize(streamFunctionBody)
+
+	// Normal function body
+	functionBody := ""
+
+	// Static components without parameters are compiled to a constant
+	staticName := ""
 
-		// Signature contains the signature of the component without the preceding keyword.
-		signature := node.Line[len("component "):]
+	// Adaptive size hints are stored in a variable
+	sizeHintName := ""
 
-		// Any signature that ends with empty parentheses should be rewritten to not include them.
-		if strings.HasSuffix(signature, "()") {
-			color.Yellow(signature)
-			color.Red("Components without definition should not include parentheses in the definition.")
+	if generated.inlined != "" && definition.Parameters == "" {
+		staticName = "static" + strings.Replace(definition.key(), ".", "", 1)
+		functionBody = "return " + staticName + returnValues
+
+		// Static components stop rendering as well when the context has been canceled
+		if compiler.Context {
+			functionBody = "if _err := ctx.Err(); _err != nil {\n\t\treturn \"\", _err\n\t}\n\n\t" + functionBody
 		}
 
-		// Add parentheses to empty parameter lists
-		if !strings.HasSuffix(signature, ")") {
-			signature += "()"
+		optimizedStreamFunctionBody = "\n\t" + writeStringCall + staticName + ")\n"
+	} else {
+		functionBody = "_b := acquire" + target.buffer + "()\n"
+
+		// Pre-size the buffer to avoid reallocations
+		if compiler.AdaptiveSizeHints {
+			sizeHintName = "sizeHint" + strings.Replace(definition.key(), ".", "", 1)
+			functionBody += "_b.Grow(sizeHint(&" + sizeHintName + "))\n"
+		} else {
+			functionBody += "_b.Grow(" + strconv.Itoa(generator.estimate(definition)) + ")\n"
 		}
 
-		// Get the necessary info from the component signature
-		componentName := signature[:strings.Index(signature, "(")]
-		componentParameters := signature[len(componentName)+1 : len(signature)-1]
-		parameterNames := extractParameterNames(componentParameters)
+		if target.buffered {
+			functionBody += "_w := acquireBufioWriter(_b)\n"
+		}
 
-		// streamFunctionCall contains the function call for the streaming version.
-		streamFunctionCall := "stream" + componentName + "(_b"
+		if returnsError {
+			functionBody += "_err := " + streamFunctionCall + "\n"
+		} else {
+			functionBody += streamFunctionCall + "\n"
+		}
 
-		if len(parameterNames) > 0 {
-			streamFunctionCall += ", " + strings.Join(parameterNames, ", ")
+		if target.buffered {
+			functionBody += "releaseBufioWriter(_w)\n"
 		}
 
-		streamFunctionCall += ")"
+		if returnsError {
+			functionBody += "\nif _err != nil {\n\trelease" + target.buffer + "(_b)\n\treturn \"\", _err\n}\n\n"
+		}
 
-		// Generate a comment line so that the linter won't complain
-		comment := "// " + componentName + " component"
+		if sizeHintName != "" {
+			functionBody += "updateSizeHint(&" + sizeHintName + ", _b.Len())\n"
+		}
 
-		// Stream function body
-		streamFunctionBody := compileChildren(node)
-		streamFunctionBody = strings.Replace(streamFunctionBody, "\n", "\n\t", -1)
-		optimizedStreamFunctionBody, inlined := optimize(streamFunctionBody)
+		functionBody += "_s := _b.String()\nrelease" + target.buffer + "(_b)\nreturn _s" + returnValues
+		functionBody = strings.Replace(functionBody, "\n", "\n\t", -1)
+	}
 
-		// Normal function body
-		functionBody := ""
+	// Components writing deferred blocks write the ones that are still queued at the end
+	// unless a component further up writes them
+	if writesDeferred(optimizedStreamFunctionBody) {
+		finish := "finishDeferred(_b, _r)\n"
 
-		if inlined != "" {
-			functionBody = strings.TrimSpace(inlined)
-		} else {
-			functionBody = "_b := acquireStringsBuilder()\n" + streamFunctionCall + "\n_pool.Put(_b)\nreturn _b.String()"
-			functionBody = strings.Replace(functionBody, "\n", "\n\t", -1)
+		if returnsError {
+			finish = "if _err := finishDeferred(_b, _r); _err != nil {\n\treturn _err\n}\n"
 		}
 
-		// Build the component code
-		code := acquireStringsBuilder()
-
-		// Normal function
-		code.WriteString(compiler.GetFileHeader())
-		code.WriteString(comment)
-		code.WriteString("\nfunc ")
-		code.WriteString(signature)
-		code.WriteString(" string {\n\t")
-		code.WriteString(functionBody)
-		code.WriteString("\n}")
-
-		// Stream function
-		code.WriteByte('\n')
-		code.WriteByte('\n')
-		code.WriteString("func stream")
-		code.WriteString(strings.Replace(signature, "(", "(_b *strings.Builder, ", 1))
-		code.WriteString(" {")
-		code.WriteString(optimizedStreamFunctionBody)
-		code.WriteString("}")
-
-		// Add the compiled component to the return values
-		components = append(components, &Component{
-			Name: componentName,
-			Code: code.String(),
-		})
-
-		// Allow the byte buffer to be re-used
-		pool.Put(code)
+		optimizedStreamFunctionBody = "\n\t" + strings.Replace(strings.TrimSuffix(beginDeferred, "\n"), "\n", "\n\t", -1) + optimizedStreamFunctionBody
+		optimizedStreamFunctionBody = strings.TrimSuffix(optimizedStreamFunctionBody, "\t") + "\t" + strings.Replace(strings.TrimSuffix(finish, "\n"), "\n", "\n\t", -1) + "\n"
 	}
 
-	return components, nil
+	// Stop rendering when the context has been canceled
+	if compiler.Context {
+		optimizedStreamFunctionBody = "\n\t" + strings.Replace(strings.TrimSuffix(contextCheck, "\n"), "\n", "\n\t", -1) + "\n" + optimizedStreamFunctionBody
+	}
+
+	if returnsError {
+		optimizedStreamFunctionBody = strings.TrimSuffix(optimizedStreamFunctionBody, "\t") + "\treturn nil\n"
+	}
+
+	// Build the component code
+	code := acquireStringsBuilder()
+
+	// Normal function
+	code.WriteString(compiler.GetFileHeader())
+
+	if staticName != "" {
+		code.WriteString("const ")
+		code.WriteString(staticName)
+		code.WriteString(" = ")
+		code.WriteString(generated.inlined)
+		code.WriteString("\n\n")
+	}
+
+	if sizeHintName != "" {
+		code.WriteString("var ")
+		code.WriteString(sizeHintName)
+		code.WriteString(" int64 = ")
+		code.WriteString(strconv.Itoa(generator.estimate(definition)))
+		code.WriteString("\n\n")
+	}
+
+	code.WriteString(comment)
+	code.WriteString("\nfunc ")
+	code.WriteString(signature)
+	code.WriteString(returnType)
+	code.WriteString(" {\n\t")
+	code.WriteString(functionBody)
+	code.WriteString("\n}")
+
+	// Stream function
+	code.WriteByte('\n')
+	code.WriteByte('\n')
+	code.WriteString("func ")
+	code.WriteString(receiver + compiler.streamName(componentName) + typeParameters + "(" + streamParameters + ")")
+	code.WriteString(streamReturnType)
+	code.WriteString(" {")
+
+	if target.prologue != "" {
+		code.WriteString("\n\t")
+		code.WriteString(strings.TrimSuffix(target.prologue, "\n"))
+	}
+
+	code.WriteString(optimizedStreamFunctionBody)
+	code.WriteString("}")
+
+	component := &Component{
+		Name:           definition.key(),
+		Code:           code.String(),
+		Receiver:       definition.Receiver,
+		TypeParameters: definition.TypeParameters,
+		Parameters:     definition.Parameters,
+		Defaults:       definition.Defaults,
+		ReturnsError:   returnsError,
+	}
+
+	// Allow the byte buffer to be re-used
+	pool.Put(code)
+	return component
+}
+
+// checkDefaults reports default values referring to the receiver or the parameters of the component.
+func (compiler *Compiler) checkDefaults(generator *generator, definition *Definition) {
+	var variables []string
+
+	if definition.Receiver != "" {
+		variables = append(variables, strings.Fields(definition.Receiver)[0])
+	}
+
+	for _, name := range extractParameterNames(definition.Parameters) {
+		variables = append(variables, strings.TrimSuffix(name, "..."))
+	}
+
+	parameters, _ := extractParameters(definition.Parameters)
+
+	for _, parameter := range parameters {
+		value, hasDefault := definition.Defaults[parameter.name]
+
+		if !hasDefault {
+			continue
+		}
+
+		if variable := referencedVariable(value, variables); variable != "" {
+			generator.fail("%s uses %s in the default value of %s, which is not in scope of the callers", definition.Name, variable, parameter.name)
+			return
+		}
+	}
 }
 
 // CompileBytes compiles a Pixy template as a byte slice and returns a slice of components.
@@ -143,6 +432,7 @@ func (compiler *Compiler) CompileString(src string) ([]*Component, error) {
 }
 
 // CompileFile compiles a Pixy template read from a file and returns a slice of components.
+// Included files are relative to the directory of the template.
 func (compiler *Compiler) CompileFile(fileIn string) ([]*Component, error) {
 	reader, err := os.Open(fileIn)
 
@@ -150,7 +440,35 @@ func (compiler *Compiler) CompileFile(fileIn string) ([]*Component, error) {
 		return nil, errors.New("Can't read from " + fileIn + "\n" + err.Error())
 	}
 
-	return compiler.Compile(reader)
+	defer reader.Close()
+	return compiler.compile(reader, fileIn)
+}
+
+// CompileFiles compiles the Pixy templates of a package and returns the components of all of them.
+// Calls between the templates know the called components, e.g. whether they return an error,
+// while CompileFile assumes that components in other templates don't return an error.
+// Templates included by one of the files shouldn't be passed as well.
+func (compiler *Compiler) CompileFiles(files ...string) ([]*Component, error) {
+	templates := make([]*template, 0, len(files))
+
+	for _, file := range files {
+		reader, err := os.Open(file)
+
+		if err != nil {
+			return nil, errors.New("Can't read from " + file + "\n" + err.Error())
+		}
+
+		parsed, err := loadTemplate(reader, file)
+		reader.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, parsed)
+	}
+
+	return compiler.compileTemplates(templates)
 }
 
 // GetFileHeader returns the file header.
@@ -158,27 +476,23 @@ func (compiler *Compiler) GetFileHeader() string {
 	return "package " + compiler.PackageName + "\n\n"
 }
 
-// GetUtilities returns the file header and utility functions
-// that are available for components.
-func (compiler *Compiler) GetUtilities() string {
-	return compiler.GetFileHeader() + `
-import (
-	"strings"
-	"sync"
-)
+// streamName returns the name of the stream function of a component.
+// Unexported components always have unexported stream functions,
+// which are camel case like the ones of exported components, e.g. streamButton.
+func (compiler *Compiler) streamName(name string) string {
+	first, _ := utf8.DecodeRuneInString(name)
 
-var _pool = sync.Pool{
-	New: func() interface{} {
-		return &strings.Builder{}
-	},
-}
+	if compiler.ExportStreamFunctions && unicode.IsUpper(first) {
+		return "Stream" + name
+	}
 
-func acquireStringsBuilder() *strings.Builder {
-	builder := _pool.Get().(*strings.Builder)
-	builder.Reset()
-	return builder
+	return "stream" + exported(name)
 }
-`
+
+// GetUtilities returns the file header and utility functions
+// that are available for components.
+func (compiler *Compiler) GetUtilities() string {
+	return compiler.GetFileHeader() + utilities(targets[compiler.Target])
 }
 
 // SaveUtilities adds the file with required function definitions to the directory.
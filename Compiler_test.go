@@ -0,0 +1,466 @@
+package pixy_test
+
+import (
+	"errors"
+	"flag"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/pixy"
+	"github.com/akyoto/assert"
+)
+
+var update = flag.Bool("update", false, "update the generated code in internal/generated")
+
+// generated lists the packages in internal/generated
+// together with the compiler used to generate them.
+// Templates listed in files are compiled with their own compiler
+// and share the utilities of the package, which only depend on the target.
+var generated = []struct {
+	directory string
+	compiler  *pixy.Compiler
+	registry  bool
+	files     map[string]*pixy.Compiler
+}{
+	{"internal/generated/builder", pixy.NewCompiler("builder"), false, map[string]*pixy.Compiler{
+		"adaptive.pixy":   {PackageName: "builder", AdaptiveSizeHints: true},
+		"fragments.pixy":  {PackageName: "builder", Fragments: true, InlineThreshold: 512},
+		"filters.pixy":    filtersCompiler("builder"),
+		"transforms.pixy": transformsCompiler("builder"),
+	}},
+	{"internal/generated/writer", &pixy.Compiler{PackageName: "writer", Target: pixy.TargetWriter}, false, nil},
+	{"internal/generated/buffer", &pixy.Compiler{PackageName: "buffer", Target: pixy.TargetBytesBuffer}, false, nil},
+	{"internal/generated/buffered", &pixy.Compiler{PackageName: "buffered", Target: pixy.TargetBufioWriter}, false, nil},
+	{"internal/generated/stream", &pixy.Compiler{PackageName: "stream", Target: pixy.TargetIOWriter, FlushAfterHead: true}, false, nil},
+	{"internal/generated/contextual", &pixy.Compiler{PackageName: "contextual", Target: pixy.TargetIOWriter, Context: true, InlineThreshold: 512}, true, nil},
+	{"internal/generated/parallel", &pixy.Compiler{PackageName: "parallel", ParallelLimit: 2, InlineThreshold: 512}, true, map[string]*pixy.Compiler{
+		"includes.pixy": pixy.NewCompiler("parallel"),
+		"registry.pixy": pixy.NewCompiler("parallel"),
+	}},
+	{"internal/generated/props", &pixy.Compiler{PackageName: "props", Props: true, InlineThreshold: 512}, false, map[string]*pixy.Compiler{
+		"macros.pixy": {PackageName: "props", Macros: map[string]*pixy.Macro{"badge": {Component: "Badge"}}},
+	}},
+	{"internal/generated/defaults", &pixy.Compiler{PackageName: "defaults", Props: true}, true, map[string]*pixy.Compiler{
+		"methods.pixy": {PackageName: "defaults", Props: true, Fragments: true},
+	}},
+	{"internal/generated/generic", &pixy.Compiler{PackageName: "generic", Props: true, Fragments: true}, true, nil},
+	{"internal/generated/ui", &pixy.Compiler{PackageName: "ui", Props: true, ExportStreamFunctions: true}, true, nil},
+	{"internal/generated/app", pixy.NewCompiler("app"), false, nil},
+	{"internal/generated/private", &pixy.Compiler{PackageName: "private", ExportStreamFunctions: true}, true, nil},
+}
+
+// constraints maps packages in internal/generated that need a newer Go version
+// than the one of the module to the build constraint of their files.
+var constraints = map[string]string{
+	"internal/generated/generic": "go1.18",
+}
+
+// standardImports maps package names to the import paths
+// that generated code can refer to.
+var standardImports = map[string]string{
+	"bufio":   "bufio",
+	"bytes":   "bytes",
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"html":    "html",
+	"io":      "io",
+	"strconv": "strconv",
+	"strings": "strings",
+	"pixy":    "github.com/aerogo/pixy",
+	"reflect": "reflect",
+	"sync":    "sync",
+	"atomic":  "sync/atomic",
+}
+
+var packageReference = regexp.MustCompile(`\b([a-z]+)\.[A-Z]`)
+
+// addImports adds an import declaration for each referenced package
+// and formats the code, similar to what goimports does with the generated files.
+func addImports(code string) (string, error) {
+	if strings.Contains(code, "\nimport (") {
+		return formatSource(code)
+	}
+
+	var paths []string
+	seen := map[string]bool{}
+
+	for _, match := range packageReference.FindAllStringSubmatch(code, -1) {
+		path, exists := standardImports[match[1]]
+
+		if !exists || seen[path] || strings.Contains(code, "import \""+path+"\"") {
+			continue
+		}
+
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	if len(paths) > 0 {
+		sort.Strings(paths)
+		header := code[:strings.Index(code, "\n\n")+2]
+		declaration := "import (\n\t\"" + strings.Join(paths, "\"\n\t\"") + "\"\n)\n\n"
+		code = header + declaration + code[len(header):]
+	}
+
+	return formatSource(code)
+}
+
+func formatSource(code string) (string, error) {
+	formatted, err := format.Source([]byte(code))
+	return string(formatted), err
+}
+
+// filtersCompiler returns a compiler with a custom filter.
+func filtersCompiler(packageName string) *pixy.Compiler {
+	compiler := pixy.NewCompiler(packageName)
+	compiler.RegisterFilter("upper", func(text string) (string, error) {
+		return strings.ToUpper(strings.TrimSpace(text)), nil
+	})
+
+	return compiler
+}
+
+// transformsCompiler returns a compiler that makes images lazy-loaded,
+// prefixes their URLs with a CDN and removes data-test attributes.
+func transformsCompiler(packageName string) *pixy.Compiler {
+	compiler := pixy.NewCompiler(packageName)
+	compiler.Transforms = []pixy.Transform{
+		func(definition *pixy.Definition) error {
+			return pixy.Walk(definition.Children, func(node pixy.Node) error {
+				element, isElement := node.(*pixy.Element)
+
+				if isElement && element.Name == "img" {
+					element.SetAttribute("loading", `"lazy"`)
+				}
+
+				return nil
+			})
+		},
+		func(definition *pixy.Definition) error {
+			return pixy.Walk(definition.Children, func(node pixy.Node) error {
+				element, isElement := node.(*pixy.Element)
+
+				if !isElement || element.Name != "img" {
+					return nil
+				}
+
+				for _, attribute := range element.Attributes {
+					if attribute.Name == "src" {
+						attribute.Value = `"https://cdn.example.com" + ` + attribute.Value
+					}
+				}
+
+				return nil
+			})
+		},
+		func(definition *pixy.Definition) error {
+			return pixy.Walk(definition.Children, func(node pixy.Node) error {
+				element, isElement := node.(*pixy.Element)
+
+				if isElement {
+					element.RemoveAttribute("data-test")
+				}
+
+				return nil
+			})
+		},
+	}
+
+	return compiler
+}
+
+func TestGenerated(t *testing.T) {
+	for _, pkg := range generated {
+		files := map[string]string{
+			"utilities.go": pkg.compiler.GetUtilities(),
+		}
+
+		sources, err := filepath.Glob(filepath.Join(pkg.directory, "*.pixy"))
+		assert.Nil(t, err)
+		var all []*pixy.Component
+
+		for _, source := range sources {
+			compiler, exists := pkg.files[filepath.Base(source)]
+
+			if !exists {
+				compiler = pkg.compiler
+			}
+
+			components, err := compiler.CompileFile(source)
+			assert.Nil(t, err)
+
+			for _, component := range components {
+				files[component.Name+".go"] = component.Code
+			}
+
+			all = append(all, components...)
+		}
+
+		if pkg.registry {
+			files["registry.go"] = pkg.compiler.GetRegistry(all)
+		}
+
+		for name, code := range files {
+			path := filepath.Join(pkg.directory, name)
+			code, err := addImports(code)
+			assert.Nil(t, err)
+			constraint, exists := constraints[pkg.directory]
+
+			if exists {
+				code = "//go:build " + constraint + "\n// +build " + constraint + "\n\n" + code
+			}
+
+			if *update {
+				assert.Nil(t, ioutil.WriteFile(path, []byte(code), 0644))
+				continue
+			}
+
+			existing, err := ioutil.ReadFile(path)
+			assert.Nil(t, err)
+			assert.Equal(t, string(existing), code)
+		}
+	}
+}
+
+func TestCompileErrorComponents(t *testing.T) {
+	_, err := pixy.CompileString("component Page\n\tProfile(1)\n\ncomponent Profile(id int) error\n\th1?= name(id)")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page calls Profile")
+
+	_, err = pixy.CompileString("component Profile(id int)\n\th1?= name(id)")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Profile uses ?=")
+}
+
+func TestCompileParallelElements(t *testing.T) {
+	_, err := pixy.CompileString("component Page\n\tparallel\n\t\th1 Hello")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page can only have component calls in a parallel block")
+}
+
+func TestCompileDefaultsOutOfScope(t *testing.T) {
+	_, err := pixy.CompileString("component Badge(count int, max int = count)\n\tspan= count")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "component Badge uses count in the default value of max, which is not in scope of the callers")
+
+	_, err = pixy.CompileString("component Badge(count int, unit Unit = Unit{count: 1})\n\tspan= count")
+	assert.Nil(t, err)
+}
+
+func TestCompileParallelNamedArguments(t *testing.T) {
+	compiler := &pixy.Compiler{PackageName: "main", Props: true}
+	components, err := compiler.CompileString("component Page\n\tparallel\n\t\tHello(person=\"World\")\n\ncomponent Hello(person string, greeting string = \"Hi\")\n\th1= greeting + person")
+	assert.Nil(t, err)
+	assert.Contains(t, components[0].Code, `HelloWith(HelloProps{Person: "World", Greeting: "Hi"})`)
+
+	_, err = pixy.CompileString("component Page\n\tparallel\n\t\tHello(person=\"World\")\n\ncomponent Hello(person string)\n\th1= person")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page calls Hello with named arguments which requires props to be enabled")
+}
+
+func TestCompileDeclaredPackageComponents(t *testing.T) {
+	components, err := pixy.CompileString("import \"example.com/app/ui\"\n\ncomponent ui.Profile(id int, size string = \"small\") error\n\ncomponent Page error\n\tui.Profile(1)\n\tparallel\n\t\tui.Profile(2)")
+	assert.Nil(t, err)
+	assert.Equal(t, len(components), 1)
+	assert.Contains(t, components[0].Code, "if _err := ui.StreamProfile(_b, 1, \"small\"); _err != nil {")
+	assert.Contains(t, components[0].Code, `return ui.Profile(2, "small") },`)
+
+	_, err = pixy.CompileString("import \"example.com/app/ui\"\n\ncomponent ui.Profile(id int) error\n\ncomponent Page\n\tui.Profile(1)")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page calls Profile which returns an error")
+}
+
+func TestCompileSameStreamFunction(t *testing.T) {
+	_, err := pixy.CompileString("component icon(name string)\n\ti= name\n\ncomponent Icon(name string)\n\tspan= name")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "component icon and Icon have the same stream function streamIcon")
+
+	compiler := &pixy.Compiler{PackageName: "main", ExportStreamFunctions: true}
+	_, err = compiler.CompileString("component icon(name string)\n\ti= name\n\ncomponent Icon(name string)\n\tspan= name")
+	assert.Nil(t, err)
+}
+
+func TestCompileDynamicCallWithoutError(t *testing.T) {
+	_, err := pixy.CompileString("component Page(name string)\n\t+Dynamic(name)")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page uses a dynamic call but doesn't return an error")
+}
+
+func TestCompileNamedArgumentsWithoutProps(t *testing.T) {
+	_, err := pixy.CompileString("component Page\n\tHello(person=\"World\")\n\ncomponent Hello(person string)\n\th1= person")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page calls Hello with named arguments")
+}
+
+func TestCompileMethodWithUnknownReceiver(t *testing.T) {
+	_, err := pixy.CompileString("component Page(posts []*Post) error\n\teach post in posts\n\t\tpost.Card\n\ncomponent (post *Post) Card error\n\th2= post.Title\n\ncomponent (author *Author) Card\n\th3= author.Name")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page calls the method Card on post whose type is unknown")
+}
+
+func TestCompileMethodCallsAndClasses(t *testing.T) {
+	components, err := pixy.CompileString("component (p *Post) Summary\n\tp.Card\n\tdiv.Card\n\ncomponent (p *Post) Card\n\th2= p.Title")
+	assert.Nil(t, err)
+	assert.Contains(t, components[0].Code, "p.streamCard(_b)")
+	assert.Contains(t, components[0].Code, "<div class='Card'>")
+}
+
+func TestCompileGoBlockWithoutComponents(t *testing.T) {
+	_, err := pixy.CompileString("go\n\tfunc double(x int) int {\n\t\treturn x * 2\n\t}")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "go blocks need a component")
+}
+
+func TestCompileFiles(t *testing.T) {
+	compiler := &pixy.Compiler{PackageName: "files"}
+	components, err := compiler.CompileFiles("testdata/files/page.pixy", "testdata/files/profile.pixy")
+	assert.Nil(t, err)
+	assert.Equal(t, len(components), 2)
+	assert.Contains(t, components[0].Code, "if _err := streamProfile(_b, 1); _err != nil {")
+
+	_, err = pixy.CompileFiles("testdata/files/page.pixy", "testdata/files/page.pixy")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "component Page is defined more than once")
+}
+
+func TestCompileFragmentInElse(t *testing.T) {
+	compiler := &pixy.Compiler{PackageName: "main", Fragments: true}
+	_, err := compiler.CompileString("component Page(ok bool)\n\tif ok\n\t\tp#yes Yes\n\telse\n\t\tp#no No")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page has the fragment no inside the block else")
+}
+
+func TestCompileIncludeCycle(t *testing.T) {
+	_, err := pixy.CompileFile("testdata/include/a.pixy")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "include cycle")
+	assert.Contains(t, err.Error(), filepath.Join("include", "b.pixy")+" -> ")
+}
+
+func TestCompileIncludeMissingFile(t *testing.T) {
+	_, err := pixy.CompileFile("testdata/include/missing.pixy")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), filepath.Join("include", "missing.pixy")+":2:2: can't include missing.svg")
+}
+
+func TestCompileUnknownFilter(t *testing.T) {
+	_, err := pixy.CompileString("component Page\n\t:sass\n\t\tbody { }")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page uses the unknown filter sass")
+}
+
+func TestCompileFilterError(t *testing.T) {
+	compiler := pixy.NewCompiler("components")
+	compiler.RegisterFilter("fail", func(text string) (string, error) {
+		return "", errors.New("invalid input")
+	})
+
+	_, err := compiler.CompileString("component Page\n\t:fail text")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Page: filter fail: invalid input")
+}
+
+func TestMarkdown(t *testing.T) {
+	markdown, _ := pixy.BuiltinFilter("markdown")
+
+	for _, test := range []struct {
+		source string
+		html   string
+	}{
+		{"# Title\nText", "<h1>Title</h1><p>Text</p>"},
+		{"First\nSecond", "<p>First\nSecond</p>"},
+		{"First\nSecond\n\nThird\n\n\nFourth", "<p>First\nSecond</p><p>Third</p><p>Fourth</p>"},
+		{"First\n\tcontinued", "<p>First\ncontinued</p>"},
+		{"- a\n\tb\n- c", "<ul><li>a\nb</li><li>c</li></ul>"},
+		{"- a\nb\n\nc", "<ul><li>a\nb</li></ul><p>c</p>"},
+		{"Text\n- a\n# Title", "<p>Text</p><ul><li>a</li></ul><h1>Title</h1>"},
+		{"```\na\n\nb\n```", "<pre><code>a\n\nb</code></pre>"},
+		{"*em* **strong** snake_case_name _em_", "<p><em>em</em> <strong>strong</strong> snake_case_name <em>em</em></p>"},
+		{"2 * 3 * 4", "<p>2 * 3 * 4</p>"},
+		{"\\*not em\\* `<b>`", "<p>*not em* <code>&lt;b&gt;</code></p>"},
+		{"[a](/x) ![b](/y.png) [c] d", "<p><a href='/x'>a</a> <img src='/y.png' alt='b'> [c] d</p>"},
+		{"```\n\tindented\n```", "<pre><code>\tindented</code></pre>"},
+		{"#hashtag\n***", "<p>#hashtag</p><hr>"},
+	} {
+		html, err := markdown(test.source)
+		assert.Nil(t, err)
+		assert.Equal(t, html, test.html)
+	}
+}
+
+func TestCompileTransformPositions(t *testing.T) {
+	compiler := pixy.NewCompiler("components")
+	var positions []string
+
+	compiler.Transforms = []pixy.Transform{func(definition *pixy.Definition) error {
+		positions = append(positions, definition.Position.String())
+
+		return pixy.Walk(definition.Children, func(node pixy.Node) error {
+			if element, isElement := node.(*pixy.Element); isElement {
+				positions = append(positions, element.Name+" "+element.Position.String())
+			}
+
+			return nil
+		})
+	}}
+
+	_, err := compiler.CompileString("// Comment\n\ncomponent Page\n\tmain\n\n\t\t\n\t\tp Text\n\ncomponent Footer\n\tfooter")
+	assert.Nil(t, err)
+	assert.DeepEqual(t, positions, []string{"3:1", "main 4:2", "p 7:3", "9:1", "footer 10:2"})
+}
+
+func TestCompileTransformErrors(t *testing.T) {
+	compiler := pixy.NewCompiler("components")
+	compiler.Transforms = []pixy.Transform{func(definition *pixy.Definition) error {
+		return pixy.Walk(definition.Children, func(node pixy.Node) error {
+			element, isElement := node.(*pixy.Element)
+
+			if isElement && element.Name == "img" && element.RemoveAttribute("alt") == "" {
+				return element.Position.Errorf("img needs an alt attribute")
+			}
+
+			return nil
+		})
+	}}
+
+	_, err := compiler.CompileFile("testdata/transform.pixy")
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), filepath.Join("testdata", "transform.pixy")+":4:3: img needs an alt attribute")
+
+	compiler.Transforms = []pixy.Transform{func(definition *pixy.Definition) error {
+		return errors.New("not allowed")
+	}}
+
+	_, err = compiler.CompileString("\ncomponent Page\n\tp Text")
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "2:1: component Page: not allowed")
+}
+
+func TestCompileMacroErrors(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		err    string
+	}{
+		{"macro icon Icon\n\ncomponent Page\n\ticon", "4:2: macro icon is missing the parameter name of Icon"},
+		{"macro icon Icon\n\ncomponent Page\n\ticon(name=\"x\", size=2)", "4:2: macro icon passes size which is not a parameter of Icon"},
+		{"macro icon Icon\n\ncomponent Page\n\ticon(name=\"x\") Text", "4:2: macro icon has no parameter for the content"},
+		{"macro icon Icon\n\ncomponent Page\n\ticon(data-name=\"x\")", "4:2: macro icon has no parameter for data-name"},
+		{"macro icon Icon\n\ncomponent Page\n\ticon(name=\"x\")\n\t\tspan", "4:2: macro icon can't have children"},
+	} {
+		_, err := pixy.CompileString(test.source + "\n\ncomponent Icon(name string)\n\ti(class=name)")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), test.err)
+	}
+}
+
+func TestCompileMacroNamedArguments(t *testing.T) {
+	components, err := pixy.CompileString("import \"example.com/app/ui\"\nmacro Button ui.Button(label=content, kind=classes)\n\ncomponent Page\n\tButton.primary(disabled) Save")
+	assert.Nil(t, err)
+	assert.Contains(t, components[0].Code, `ui.StreamButtonWith(_b, ui.ButtonProps{Kind: "primary", Disabled: true, Label: "Save"})`)
+}
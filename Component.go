@@ -2,6 +2,23 @@ package pixy
 
 // Component represents a single, reusable template.
 type Component struct {
+	// Name is the name of the component. Methods are prefixed
+	// with the name of the receiver type, e.g. "Post.Card".
 	Name string
 	Code string
+
+	// Receiver contains the receiver of components defined as methods.
+	Receiver string
+
+	// TypeParameters contains the Go type parameter list of generic components.
+	TypeParameters string
+
+	// Parameters contains the Go parameter list of the component without the context.
+	Parameters string
+
+	// Defaults maps parameter names to their default values as Go expressions.
+	Defaults map[string]string
+
+	// ReturnsError tells whether the component function returns an error.
+	ReturnsError bool
 }
@@ -0,0 +1,241 @@
+package pixy
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// evaluate returns the string representation of a constant Go expression
+// like "Hello " + "World". The constants map contains the Go literals
+// that identifiers in the expression refer to. The second return value
+// reports whether the expression could be evaluated at compile time.
+func evaluate(expression string, constants map[string]string) (string, bool) {
+	value, _ := evaluateExpression(expression, constants)
+
+	if value == nil {
+		return "", false
+	}
+
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value), true
+
+	case constant.Bool:
+		return strconv.FormatBool(constant.BoolVal(value)), true
+
+	case constant.Int:
+		return value.ExactString(), true
+
+	case constant.Float:
+		number, _ := constant.Float64Val(value)
+		return strconv.FormatFloat(number, 'g', -1, 64), true
+	}
+
+	return "", false
+}
+
+// literal returns a Go literal for the value of a constant Go expression.
+func literal(expression string, constants map[string]string) (string, bool) {
+	value, _ := evaluateExpression(expression, constants)
+
+	if value == nil {
+		return "", false
+	}
+
+	switch value.Kind() {
+	case constant.String, constant.Bool, constant.Int:
+		return value.ExactString(), true
+
+	case constant.Float:
+		number, _ := constant.Float64Val(value)
+		return strconv.FormatFloat(number, 'g', -1, 64), true
+	}
+
+	return "", false
+}
+
+// isStringLiteral tells you whether the expression is a single string literal like "primary".
+func isStringLiteral(expression string) bool {
+	tree, err := parser.ParseExpr(expression)
+
+	if err != nil {
+		return false
+	}
+
+	literal, isLiteral := tree.(*ast.BasicLit)
+	return isLiteral && literal.Kind == token.STRING
+}
+
+// evaluateExpression parses the expression and returns its constant value or nil
+// together with its integer type.
+func evaluateExpression(expression string, constants map[string]string) (constant.Value, string) {
+	tree, err := parser.ParseExpr(expression)
+
+	if err != nil {
+		return nil, ""
+	}
+
+	return evaluateNode(tree, constants)
+}
+
+// evaluateNode returns the constant value of an expression node or nil.
+// Values converted to an integer type like byte(255) keep the type, which is
+// returned as well, and the expression is only constant if the results fit into it.
+// The values of untyped expressions have an empty type.
+func evaluateNode(expression ast.Expr, constants map[string]string) (constant.Value, string) {
+	switch expression := expression.(type) {
+	case *ast.BasicLit:
+		// Characters are integers in Go, which is rarely what a template means
+		if expression.Kind == token.CHAR || expression.Kind == token.IMAG {
+			return nil, ""
+		}
+
+		value := constant.MakeFromLiteral(expression.Value, expression.Kind, 0)
+
+		if value.Kind() == constant.Unknown {
+			return nil, ""
+		}
+
+		return value, ""
+
+	case *ast.Ident:
+		if literal, exists := constants[expression.Name]; exists {
+			return evaluateExpression(literal, nil)
+		}
+
+		switch expression.Name {
+		case "true":
+			return constant.MakeBool(true), ""
+		case "false":
+			return constant.MakeBool(false), ""
+		}
+
+	case *ast.ParenExpr:
+		return evaluateNode(expression.X, constants)
+
+	case *ast.CallExpr:
+		conversion, isIdentifier := expression.Fun.(*ast.Ident)
+
+		if !isIdentifier || integerTypes[conversion.Name] == 0 || len(expression.Args) != 1 || expression.Ellipsis.IsValid() {
+			return nil, ""
+		}
+
+		operand, _ := evaluateNode(expression.Args[0], constants)
+		return typed(operand, conversion.Name)
+
+	case *ast.UnaryExpr:
+		operand, typeName := evaluateNode(expression.X, constants)
+
+		if operand == nil {
+			return nil, ""
+		}
+
+		switch {
+		case expression.Op == token.SUB && isNumeric(operand):
+			return typed(constant.UnaryOp(token.SUB, operand, 0), typeName)
+		case expression.Op == token.NOT && operand.Kind() == constant.Bool:
+			return constant.UnaryOp(token.NOT, operand, 0), ""
+		}
+
+	case *ast.BinaryExpr:
+		left, leftType := evaluateNode(expression.X, constants)
+		right, rightType := evaluateNode(expression.Y, constants)
+
+		if left == nil || right == nil || (leftType != "" && rightType != "" && leftType != rightType) {
+			return nil, ""
+		}
+
+		switch {
+		case expression.Op == token.ADD && left.Kind() == constant.String && right.Kind() == constant.String:
+			return constant.BinaryOp(left, token.ADD, right), ""
+
+		case (expression.Op == token.ADD || expression.Op == token.SUB || expression.Op == token.MUL) && left.Kind() == constant.Int && right.Kind() == constant.Int:
+			return typed(constant.BinaryOp(left, expression.Op, right), leftType+rightType)
+		}
+	}
+
+	return nil, ""
+}
+
+// integerTypes maps the predeclared integer types to their size in bits.
+// Signed types have a negative size. int, uint and uintptr are assumed to have
+// 32 bits so that folded values fit into them on every platform.
+var integerTypes = map[string]int{
+	"byte":    8,
+	"int":     -32,
+	"int8":    -8,
+	"int16":   -16,
+	"int32":   -32,
+	"int64":   -64,
+	"rune":    -32,
+	"uint":    32,
+	"uint8":   8,
+	"uint16":  16,
+	"uint32":  32,
+	"uint64":  64,
+	"uintptr": 32,
+}
+
+// typed returns the value with the integer type if the value fits into it.
+// At runtime the operations would overflow, therefore other values are not constant.
+func typed(value constant.Value, typeName string) (constant.Value, string) {
+	bits := integerTypes[typeName]
+
+	if value == nil {
+		return nil, ""
+	}
+
+	if bits == 0 {
+		return value, typeName
+	}
+
+	if value.Kind() != constant.Int {
+		return nil, ""
+	}
+
+	var minimum, maximum constant.Value
+
+	if bits < 0 {
+		maximum = constant.Shift(constant.MakeInt64(1), token.SHL, uint(-bits-1))
+		minimum = constant.UnaryOp(token.SUB, maximum, 0)
+	} else {
+		maximum = constant.Shift(constant.MakeInt64(1), token.SHL, uint(bits))
+		minimum = constant.MakeInt64(0)
+	}
+
+	if constant.Compare(value, token.LSS, minimum) || constant.Compare(value, token.GEQ, maximum) {
+		return nil, ""
+	}
+
+	return value, typeName
+}
+
+// defaultType returns the type that a variable initialized with the constant literal has.
+func defaultType(literal string) string {
+	value, _ := evaluateExpression(literal, nil)
+
+	if value == nil {
+		return ""
+	}
+
+	switch value.Kind() {
+	case constant.String:
+		return "string"
+	case constant.Bool:
+		return "bool"
+	case constant.Int:
+		return "int"
+	case constant.Float:
+		return "float64"
+	}
+
+	return ""
+}
+
+// isNumeric reports whether the constant is an integer or a floating-point number.
+func isNumeric(value constant.Value) bool {
+	return value.Kind() == constant.Int || value.Kind() == constant.Float
+}
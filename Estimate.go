@@ -0,0 +1,70 @@
+package pixy
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// dynamicSizeEstimate is the estimated size of a value written by an expression.
+	dynamicSizeEstimate = 16
+
+	// callSizeEstimate is the estimated output size of a component in a different template.
+	callSizeEstimate = 64
+)
+
+// estimate returns the estimated output size of a component in bytes.
+// It's the sum of the static bytes and a fixed estimate for each dynamic value.
+// Calls to components in the same template add the estimate of the called component.
+func (generator *generator) estimate(definition *Definition) int {
+	if generator.estimating[definition.key()] {
+		return 0
+	}
+
+	generator.estimating[definition.key()] = true
+	defer delete(generator.estimating, definition.key())
+	size := 0
+
+	for _, line := range strings.Split(generator.component(definition).code, "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "if _err := ")
+		literal, isLiteral := writtenLiteral(line)
+
+		switch {
+		case isLiteral:
+			unquoted, _ := strconv.Unquote(literal)
+			size += len(unquoted)
+
+		case strings.HasPrefix(line, writeStringCall) || strings.HasPrefix(line, "writeEscaped(_b, "):
+			size += dynamicSizeEstimate
+
+		case strings.HasPrefix(line, "stream") || strings.HasPrefix(line, "Stream") || strings.HasPrefix(line, parallelFunction):
+			definitions := generator.streams
+
+			if strings.HasPrefix(line, parallelFunction) {
+				line = line[len(parallelFunction):]
+				definitions = generator.definitions
+			}
+
+			name, _, _ := splitTypeParameters(line)
+			called, exists := definitions[name]
+
+			// Calls with props
+			if !exists {
+				called, exists = definitions[strings.TrimSuffix(name, "With")]
+			}
+
+			// Components in other packages are only declared
+			if exists && !strings.Contains(called.Name, ".") {
+				size += generator.estimate(called)
+			} else {
+				size += callSizeEstimate
+			}
+
+		// Calls to methods and to components in other packages
+		case strings.Contains(line, ".stream") || strings.Contains(line, ".Stream"):
+			size += callSizeEstimate
+		}
+	}
+
+	return size
+}
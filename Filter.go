@@ -0,0 +1,53 @@
+package pixy
+
+import "strings"
+
+// builtinFilters contains the filters that are available without registration.
+var builtinFilters = map[string]func(string) (string, error){
+	"markdown": markdown,
+	"css":      css,
+	"js":       js,
+}
+
+// RegisterFilter adds a filter that transforms the text of :name blocks at compile time.
+// Filters with the name of a built-in filter replace it.
+func (compiler *Compiler) RegisterFilter(name string, filter func(string) (string, error)) {
+	if compiler.filters == nil {
+		compiler.filters = map[string]func(string) (string, error){}
+	}
+
+	compiler.filters[name] = filter
+}
+
+// BuiltinFilter returns the built-in filter with the given name:
+// markdown, css or js.
+func BuiltinFilter(name string) (func(string) (string, error), bool) {
+	filter, exists := builtinFilters[name]
+	return filter, exists
+}
+
+// css returns a style element with the style sheet.
+func css(text string) (string, error) {
+	return "<style>" + compactLines(text) + "</style>", nil
+}
+
+// js returns a script element with the script.
+func js(text string) (string, error) {
+	return "<script>" + compactLines(text) + "</script>", nil
+}
+
+// compactLines removes the indentation and empty lines of the text.
+func compactLines(text string) string {
+	lines := strings.Split(text, "\n")
+	compacted := lines[:0]
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line != "" {
+			compacted = append(compacted, line)
+		}
+	}
+
+	return strings.Join(compacted, "\n")
+}
@@ -0,0 +1,117 @@
+package pixy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// fragments returns the definition of a component rendering one of the fragments
+// of the given component, selected by a fragment parameter that is added to the
+// parameters of the component. Fragments are elements with a constant id and
+// fragment blocks. Fragments inside if blocks keep their conditions, while fragments
+// within loops or blocks declaring variables are skipped because they depend on those
+// variables. Other blocks like else can't be rendered on their own and are reported.
+// Unknown fragment names return an error. It returns nil if there are no fragments.
+func (generator *generator) fragments(component *Definition) *Definition {
+	var branches []Node
+	names := map[string]bool{}
+
+	var collect func(nodes []Node, conditions []string, blocked string)
+
+	add := func(name string, children []Node, conditions []string, blocked string) {
+		if blocked != "" {
+			generator.fail("%s has the fragment %s inside the block %s, which can't be rendered on its own", component.Name, name, blocked)
+			return
+		}
+
+		if names[name] {
+			return
+		}
+
+		names[name] = true
+		statement := "if fragment == " + strconv.Quote(name)
+
+		if len(branches) > 0 {
+			statement = "else " + statement
+		}
+
+		// The conditions of the enclosing if blocks are checked from the inside out
+		for index := len(conditions) - 1; index >= 0; index-- {
+			children = []Node{&Block{
+				Statement: conditions[index],
+				Children:  children,
+			}}
+		}
+
+		branches = append(branches, &Block{
+			Statement: statement,
+			Children:  children,
+		})
+	}
+
+	collect = func(nodes []Node, conditions []string, blocked string) {
+		for _, child := range nodes {
+			switch child := child.(type) {
+			case *Element:
+				for _, attribute := range child.Attributes {
+					if attribute.Name != "id" {
+						continue
+					}
+
+					id, isConstant := evaluate(attribute.Value, nil)
+
+					if isConstant {
+						add(id, []Node{child}, conditions, blocked)
+					}
+				}
+
+				collect(child.Children, conditions, blocked)
+
+			case *Fragment:
+				add(child.Name, child.Children, conditions, blocked)
+				collect(child.Children, conditions, blocked)
+
+			case *Block:
+				switch {
+				case strings.HasPrefix(child.Statement, "for ") || declaredNames(child.Statement) != nil:
+					continue
+
+				case strings.HasPrefix(child.Statement, "if ") && blocked == "":
+					collect(child.Children, append(conditions[:len(conditions):len(conditions)], child.Statement), blocked)
+
+				case blocked == "":
+					collect(child.Children, conditions, child.Statement)
+
+				default:
+					collect(child.Children, conditions, blocked)
+				}
+			}
+		}
+	}
+
+	collect(component.Children, nil, "")
+
+	if len(branches) == 0 {
+		return nil
+	}
+
+	branches = append(branches, &Block{
+		Statement: "else",
+		Children:  []Node{unknownFragment{}},
+	})
+
+	fragments := &Definition{
+		Name:           component.Name + "Fragment",
+		Receiver:       component.Receiver,
+		TypeParameters: component.TypeParameters,
+		Parameters:     strings.TrimSuffix("fragment string, "+component.Parameters, ", "),
+		ReturnsError:   true,
+		Children:       branches,
+	}
+
+	generator.definitions[fragments.key()] = fragments
+	return fragments
+}
+
+// unknownFragment returns an error for a fragment name that doesn't exist.
+type unknownFragment struct{}
@@ -0,0 +1,931 @@
+package pixy
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/akyoto/color"
+)
+
+// generator generates the stream function bodies
+// for the components of a single template.
+type generator struct {
+	compiler    *Compiler
+	definitions map[string]*Definition
+	streams     map[string]*Definition
+	results     map[string]*result
+	inlining    map[string]bool
+	estimating  map[string]bool
+	constants   map[string]string
+	current     *Definition
+	variables   []string
+	err         error
+}
+
+// result is the generated stream function body of a component.
+// If the component output is entirely static, inlined contains
+// the output as a quoted Go string.
+type result struct {
+	code    string
+	inlined string
+}
+
+// newGenerator creates a generator for the given component definitions
+// and the declared components of other packages using the options of the compiler.
+func newGenerator(compiler *Compiler, definitions []*Definition, declarations []*Definition) *generator {
+	generator := &generator{
+		compiler:    compiler,
+		definitions: make(map[string]*Definition, len(definitions)),
+		streams:     make(map[string]*Definition, len(definitions)),
+		results:     make(map[string]*result, len(definitions)),
+		inlining:    map[string]bool{},
+		estimating:  map[string]bool{},
+	}
+
+	for _, definition := range definitions {
+		if _, exists := generator.definitions[definition.key()]; exists {
+			generator.fail("%s is defined more than once", definition.key())
+		}
+
+		generator.definitions[definition.key()] = definition
+		stream := compiler.streamName(definition.Name)
+
+		if definition.Receiver != "" {
+			stream = receiverType(definition.Receiver) + "." + stream
+		}
+
+		// Names that only differ in the case of the first letter have the same stream function
+		if existing, exists := generator.streams[stream]; exists && existing.key() != definition.key() {
+			generator.fail("%s and %s have the same stream function %s", existing.Name, definition.Name, stream)
+		}
+
+		generator.streams[stream] = definition
+	}
+
+	// Components in other packages are known by their qualified name
+	for _, declaration := range declarations {
+		generator.definitions[declaration.Name] = declaration
+	}
+
+	return generator
+}
+
+// component returns the optimized stream function body of a component.
+func (generator *generator) component(definition *Definition) *result {
+	existing, exists := generator.results[definition.key()]
+
+	if exists {
+		return existing
+	}
+
+	// Mark the component as dynamic while it's being generated
+	// so that recursive calls don't get inlined.
+	generator.results[definition.key()] = &result{}
+	generator.inlining[definition.key()] = true
+
+	// The component might be generated while another one is being inlined
+	callerConstants := generator.constants
+	caller := generator.current
+	generator.constants = nil
+	generator.current = definition
+
+	body := generator.children(definition.Children)
+	_, inlined := optimize(body)
+
+	generator.constants = callerConstants
+	generator.current = caller
+
+	generated := &result{
+		code:    body,
+		inlined: inlined,
+	}
+
+	generator.results[definition.key()] = generated
+	delete(generator.inlining, definition.key())
+	return generated
+}
+
+// inline returns the code of a component call with the body of the called component
+// or an empty string if the call can't be inlined. Only components in the same template
+// that don't have parameters or are called with constant arguments are inlined.
+func (generator *generator) inline(call *Call) string {
+	definition, exists := generator.definitions[call.Name]
+
+	// Generic components, methods and components in other packages are never inlined
+	if !exists || generator.inlining[call.Name] || definition.TypeParameters != "" || call.Receiver != "" || call.Package != "" {
+		return ""
+	}
+
+	// Errors can only be returned from components that return errors
+	if generator.returnsError(call.Name) && !generator.returnsError(generator.current.key()) {
+		return ""
+	}
+
+	// Components without parameters
+	if definition.Parameters == "" {
+		if call.Arguments != "" {
+			return ""
+		}
+
+		generated := generator.component(definition)
+
+		// Static components are always inlined
+		if generated.inlined != "" {
+			return write(generated.inlined)
+		}
+
+		if len(generated.code) > generator.compiler.InlineThreshold {
+			return ""
+		}
+
+		return generated.code
+	}
+
+	// Components with constant arguments
+	parameters, ok := extractParameters(definition.Parameters)
+
+	if !ok {
+		return ""
+	}
+
+	arguments := splitArguments(call.Arguments)
+
+	if len(arguments) != len(parameters) || isVariadic(parameters) {
+		return ""
+	}
+
+	values := make(map[string]string, len(parameters))
+	constants := make(map[string]string, len(parameters))
+
+	for index, parameter := range parameters {
+		value, isConstant := literal(arguments[index], generator.constants)
+
+		if !isConstant {
+			return ""
+		}
+
+		values[parameter.name] = value
+
+		// Only values that are written the same way as at runtime are folded.
+		// Integers keep their type so that results which would overflow aren't folded.
+		switch {
+		case parameter.typeName == "string" || parameter.typeName == "bool":
+			constants[parameter.name] = value
+		case integerTypes[parameter.typeName] != 0:
+			constants[parameter.name] = parameter.typeName + "(" + value + ")"
+		}
+	}
+
+	callerConstants := generator.constants
+	generator.constants = constants
+	generator.inlining[call.Name] = true
+	body := generator.children(definition.Children)
+	delete(generator.inlining, call.Name)
+	generator.constants = callerConstants
+
+	if len(body) > generator.compiler.InlineThreshold {
+		return ""
+	}
+
+	_, inlined := optimize(body)
+
+	if inlined != "" {
+		return write(inlined)
+	}
+
+	// Parameters that are still referenced after constant folding
+	// are declared as variables in a new block.
+	declarations := ""
+
+	for _, parameter := range parameters {
+		if !references(body, parameter.name) {
+			continue
+		}
+
+		if !basicTypes[parameter.typeName] {
+			return ""
+		}
+
+		value := values[parameter.name]
+
+		if defaultType(value) != parameter.typeName {
+			value = parameter.typeName + "(" + value + ")"
+		}
+
+		declarations += parameter.name + " := " + value + "\n"
+	}
+
+	if declarations == "" {
+		return body
+	}
+
+	return "{\n" + declarations + body + "}"
+}
+
+// shadow removes the constants that are shadowed by variables declared in a statement.
+// It returns the previous constants which need to be restored afterwards.
+func (generator *generator) shadow(names []string) map[string]string {
+	previous := generator.constants
+
+	if len(previous) == 0 || len(names) == 0 {
+		return previous
+	}
+
+	generator.constants = make(map[string]string, len(previous))
+
+	for name, value := range previous {
+		generator.constants[name] = value
+	}
+
+	for _, name := range names {
+		delete(generator.constants, strings.TrimSpace(name))
+	}
+
+	return previous
+}
+
+// Generates the code for a list of nodes.
+func (generator *generator) children(nodes []Node) string {
+	output := ""
+
+	for _, child := range nodes {
+		code := strings.TrimSpace(generator.node(child))
+
+		if len(code) > 0 {
+			if strings.HasPrefix(code, "else {") || strings.HasPrefix(code, "else if ") {
+				output = strings.TrimRight(output, "\n") + code + "\n"
+			} else {
+				output += code + "\n"
+			}
+		}
+	}
+
+	return output
+}
+
+// declare adds the names of variables declared in a statement to the variables in scope.
+// It returns the previous variables which need to be restored afterwards.
+func (generator *generator) declare(names []string) []string {
+	previous := generator.variables
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		if name != "_" && name != "" {
+			generator.variables = append(generator.variables[:len(generator.variables):len(generator.variables)], name)
+		}
+	}
+
+	return previous
+}
+
+// Generates the code for a single node.
+func (generator *generator) node(child Node) string {
+	switch child := child.(type) {
+	case *Element:
+		return generator.element(child)
+
+	case *Call:
+		return generator.call(child)
+
+	case *DynamicCall:
+		return generator.dynamicCall(child)
+
+	case *Block:
+		names := declaredNames(child.Statement)
+		previous := generator.shadow(names)
+		variables := generator.declare(names)
+		code := child.Statement + " {\n"
+
+		if strings.HasPrefix(child.Statement, "for ") {
+			code += generator.loop()
+		}
+
+		code += generator.children(child.Children) + "}"
+		generator.constants = previous
+		generator.variables = variables
+		return code
+
+	case *Each:
+		names := strings.Split(child.Iterator, ",")
+		previous := generator.shadow(names)
+		variables := generator.declare(names)
+
+		defer func() {
+			generator.constants = previous
+			generator.variables = variables
+		}()
+
+		if child.Reversed {
+			return fmt.Sprintf("{\n_s := %s\nfor _i := len(_s)-1; _i >= 0; _i-- {\n%s := _s[_i]\n%s%s}\n}", child.Slice, child.Iterator, generator.loop(), generator.children(child.Children))
+		}
+
+		return "for _, " + child.Iterator + " := range " + child.Slice + " {\n" + generator.loop() + generator.children(child.Children) + "}"
+
+	case *Embed:
+		return write(child.Expression)
+
+	case *Flush:
+		return generator.flush()
+
+	case unknownFragment:
+		return "return errUnknownFragment(fragment)\n"
+
+	case *Parallel:
+		return generator.parallel(child)
+
+	case *Deferred:
+		return generator.deferred(child)
+
+	case *Fragment:
+		return generator.children(child.Children)
+
+	case *Filter:
+		return generator.filter(child)
+	}
+
+	return ""
+}
+
+// Generates the code for an element, its contents and its children.
+func (generator *generator) element(element *Element) string {
+	code := generator.tag(element)
+
+	switch {
+	case element.Fallible:
+		code += generator.fallible(element.Expression, element.Raw)
+	case element.Expression != "":
+		code += generator.expression(element.Expression, element.Raw)
+	case element.Text != "":
+		code += writeString(element.Text)
+	}
+
+	code += generator.children(element.Children)
+
+	// Deferred content is written at the end of the document
+	if element.Name == "body" && generator.defers(element.Children, map[string]bool{}) {
+		code += generator.flushDeferred()
+	}
+
+	code += endTag(element.Name)
+
+	// Let the browser load the assets in the head while the body is rendered
+	if element.Name == "head" && generator.compiler.FlushAfterHead {
+		code += generator.flush()
+	}
+
+	return code
+}
+
+// Generates the code that writes the output of a filter.
+// Filters are applied at compile time so that the output is a static string.
+func (generator *generator) filter(filter *Filter) string {
+	transform, exists := generator.compiler.filters[filter.Name]
+
+	if !exists {
+		transform, exists = BuiltinFilter(filter.Name)
+	}
+
+	if !exists {
+		generator.fail("%s uses the unknown filter %s", generator.current.Name, filter.Name)
+		return ""
+	}
+
+	output, err := transform(filter.Text)
+
+	if err != nil {
+		generator.fail("%s: filter %s: %v", generator.current.Name, filter.Name, err)
+		return ""
+	}
+
+	return writeString(output)
+}
+
+// Generates the code that flushes the output written so far.
+// Only targets that stream to a client can be flushed.
+func (generator *generator) flush() string {
+	if !targets[generator.compiler.Target].flushable {
+		return ""
+	}
+
+	return "flush(_b)\n"
+}
+
+// Generates the code for a component call.
+func (generator *generator) call(call *Call) string {
+	arguments := "_b"
+
+	// The context is passed on before the writer
+	if generator.compiler.Context {
+		arguments = "ctx, _b"
+	}
+
+	function := generator.compiler.streamName(call.Name)
+	names, values := call.NamedArguments()
+
+	// Components in other packages have exported stream functions
+	if call.Package != "" {
+		function = call.Package + ".Stream" + call.Name
+	}
+
+	switch {
+	case call.Receiver != "":
+		// Components defined as methods are called on the receiver
+		if names != nil {
+			generator.fail("%s calls the method %s with named arguments which are not supported for methods", generator.current.Name, call.Name)
+			return ""
+		}
+
+		call = generator.withDefaults(call)
+		function = call.Receiver + "." + function
+
+		if call.Arguments != "" {
+			arguments += ", " + call.Arguments
+		}
+
+	case names != nil:
+		// Named arguments are passed in the props struct
+		props := generator.props(call, names, values)
+
+		if props == "" {
+			return ""
+		}
+
+		function += "With"
+		arguments += ", " + props
+
+	default:
+		call = generator.withDefaults(call)
+		inlined := generator.inline(call)
+
+		if inlined != "" {
+			return inlined
+		}
+
+		if call.Arguments != "" {
+			arguments += ", " + call.Arguments
+		}
+	}
+
+	code := function + typeArguments(call.TypeArguments) + "(" + arguments + ")"
+
+	if generator.returnsError(generator.key(call)) {
+		if !generator.returnsError(generator.current.key()) {
+			generator.fail("%s calls %s which returns an error, therefore it needs to be declared with an error result as well", generator.current.Name, call.Name)
+			return ""
+		}
+
+		return "if _err := " + code + "; _err != nil {\nreturn _err\n}"
+	}
+
+	return code
+}
+
+// props returns the props struct literal passing the named arguments of a call.
+// Omitted arguments use the default values of the parameters.
+func (generator *generator) props(call *Call, names []string, values []string) string {
+	if !generator.compiler.Props && call.Package == "" {
+		generator.fail("%s calls %s with named arguments which requires props to be enabled", generator.current.Name, call.Name)
+		return ""
+	}
+
+	propsName := call.Name + "Props"
+
+	if call.Package != "" {
+		propsName = call.Package + "." + propsName
+	}
+
+	fields := make([]string, len(names))
+
+	for index, name := range names {
+		fields[index] = exported(name) + ": " + values[index]
+	}
+
+	definition, exists := generator.definitions[generator.key(call)]
+
+	if exists && definition.Defaults != nil {
+		parameters, _ := extractParameters(definition.Parameters)
+
+		for _, parameter := range parameters {
+			value, hasDefault := definition.Defaults[parameter.name]
+
+			if hasDefault && !contains(names, parameter.name) {
+				fields = append(fields, exported(parameter.name)+": "+value)
+			}
+		}
+	}
+
+	return propsName + typeArguments(call.TypeArguments) + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// withDefaults returns the call with the default values of the omitted trailing parameters
+// appended to its arguments. Calls to components with variadic parameters are returned as is.
+func (generator *generator) withDefaults(call *Call) *Call {
+	definition, exists := generator.definitions[generator.key(call)]
+
+	if !exists || definition.Defaults == nil {
+		return call
+	}
+
+	parameters, ok := extractParameters(definition.Parameters)
+
+	if !ok || isVariadic(parameters) {
+		return call
+	}
+
+	arguments := splitRaw(call.Arguments)
+
+	if len(arguments) >= len(parameters) {
+		return call
+	}
+
+	for len(arguments) < len(parameters) {
+		value, hasDefault := definition.Defaults[parameters[len(arguments)].name]
+
+		if !hasDefault {
+			return call
+		}
+
+		arguments = append(arguments, value)
+	}
+
+	completed := *call
+	completed.Arguments = strings.Join(arguments, ", ")
+	return &completed
+}
+
+// Generates the code for a parallel block.
+// The components are rendered concurrently using their normal functions
+// and the outputs are written in the order of the calls.
+func (generator *generator) parallel(parallel *Parallel) string {
+	code := "{\n_r, _err := renderParallel(" + strconv.Itoa(generator.compiler.ParallelLimit) + ",\n"
+
+	for _, child := range parallel.Children {
+		call, isCall := child.(*Call)
+
+		if !isCall {
+			generator.fail("%s can only have component calls in a parallel block", generator.current.Name)
+			return ""
+		}
+
+		function := call.Name
+		arguments := ""
+		names, values := call.NamedArguments()
+
+		switch {
+		case names != nil && call.Receiver != "":
+			generator.fail("%s calls the method %s with named arguments which are not supported for methods", generator.current.Name, call.Name)
+			return ""
+
+		case names != nil:
+			// Named arguments are passed in the props struct
+			arguments = generator.props(call, names, values)
+
+			if arguments == "" {
+				return ""
+			}
+
+			function += "With"
+
+		default:
+			arguments = generator.withDefaults(call).Arguments
+		}
+
+		if generator.compiler.Context {
+			arguments = strings.TrimSuffix("ctx, "+arguments, ", ")
+		}
+
+		function += typeArguments(call.TypeArguments) + "(" + arguments + ")"
+
+		// Methods and components in other packages are qualified
+		if call.Receiver != "" {
+			function = call.Receiver + "." + function
+		} else if call.Package != "" {
+			function = call.Package + "." + function
+		}
+
+		if generator.returnsError(generator.key(call)) {
+			if !generator.returnsError(generator.current.key()) {
+				generator.fail("%s calls %s which returns an error, therefore it needs to be declared with an error result as well", generator.current.Name, call.Name)
+				return ""
+			}
+		} else {
+			function += ", nil"
+		}
+
+		code += parallelFunction + function + " },\n"
+	}
+
+	code += ")\n"
+
+	if generator.returnsError(generator.current.key()) {
+		code += "if _err != nil {\nreturn _err\n}\n"
+	} else {
+		code = strings.Replace(code, "_r, _err :=", "_r, _ :=", 1)
+	}
+
+	return code + "for _, _s := range _r {\n" + write("_s") + "}\n}"
+}
+
+// Generates the code for a deferred block.
+// The children are rendered in the background into a separate buffer
+// while the placeholder is written in place.
+func (generator *generator) deferred(deferred *Deferred) string {
+	target := targets[generator.compiler.Target]
+	code := "{\n"
+
+	// The content is rendered after the variables of the current iteration may have changed
+	for _, variable := range generator.variables {
+		code += variable + " := " + variable + "\n"
+	}
+
+	buffer := "*strings.Builder"
+
+	if target.buffer == "BytesBuffer" {
+		buffer = "*bytes.Buffer"
+	}
+
+	if target.buffered {
+		code += "_id := deferContent(_b, func(_d " + buffer + ") error {\n_b := acquireBufioWriter(_d)\ndefer releaseBufioWriter(_b)\n"
+	} else {
+		code += "_id := deferContent(_b, func(_b " + buffer + ") error {\n"
+	}
+
+	content := generator.children(deferred.Children)
+
+	// Nested deferred blocks are written at the end of the content
+	if writesDeferred(content) {
+		code += beginDeferred + content + "return finishDeferred(_b, _r)\n"
+	} else {
+		code += content + "return nil\n"
+	}
+
+	code += "})\n"
+	code += writeString("<div id='pixy-deferred-")
+	code += write("_id")
+	code += writeString("'>")
+	code += generator.children(deferred.Placeholder)
+	code += writeString("</div>")
+	return code + "}"
+}
+
+// Generates the code that writes the content of the deferred blocks.
+func (generator *generator) flushDeferred() string {
+	if generator.returnsError(generator.current.key()) {
+		return "if _err := flushDeferred(_b); _err != nil {\nreturn _err\n}\n"
+	}
+
+	return "flushDeferred(_b)\n"
+}
+
+// defers tells you whether the nodes or the components they call in the same template
+// contain deferred blocks. Other components write their deferred blocks at their end.
+func (generator *generator) defers(nodes []Node, visited map[string]bool) bool {
+	found := false
+
+	_ = Walk(nodes, func(node Node) error {
+		switch node := node.(type) {
+		case *Deferred:
+			found = true
+
+		case *Call:
+			definition, exists := generator.definitions[node.Name]
+
+			if exists && node.Receiver == "" && node.Package == "" && !visited[node.Name] {
+				visited[node.Name] = true
+				found = found || generator.defers(definition.Children, visited)
+			}
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+// writesDeferred tells you whether the code queues or writes deferred blocks.
+func writesDeferred(code string) bool {
+	return strings.Contains(code, "deferContent(") || strings.Contains(code, "flushDeferred(")
+}
+
+// beginDeferred queues the deferred blocks written to _b in a new render
+// unless the code writing to _b further up has started one.
+// The deferred blocks of a new render need to be written with finishDeferred.
+const beginDeferred = "_r := beginDeferred(_b)\ndefer endDeferred(_b, _r)\n"
+
+// parallelFunction is the start of a function rendering a component in a parallel block.
+const parallelFunction = "func() (string, error) { return "
+
+// Generates the code for a call to a component selected at runtime.
+// The call fails if the component doesn't exist or the arguments don't match,
+// therefore the calling component needs to return an error.
+func (generator *generator) dynamicCall(call *DynamicCall) string {
+	if !generator.returnsError(generator.current.key()) {
+		generator.fail("%s uses a dynamic call but doesn't return an error", generator.current.Name)
+		return ""
+	}
+
+	arguments := "_b"
+
+	// The context is passed on before the writer
+	if generator.compiler.Context {
+		arguments = "ctx, _b"
+	}
+
+	return "if _err := streamDynamic(" + arguments + ", " + call.Arguments + "); _err != nil {\nreturn _err\n}"
+}
+
+// returnsError tells whether the stream function of a component returns an error.
+// Components in other templates only return errors if the context is enabled.
+func (generator *generator) returnsError(name string) bool {
+	if generator.compiler.Context {
+		return true
+	}
+
+	definition, exists := generator.definitions[name]
+	return exists && definition.ReturnsError
+}
+
+// key returns the key of the component called by the call. The receiver type
+// of a method call is known if the receiver is the receiver or a parameter
+// of the current component. Otherwise methods with the same name need to agree
+// on whether they return an error. Components in other packages are keyed
+// by their qualified name, which only exists if the template declares them.
+func (generator *generator) key(call *Call) string {
+	if call.Package != "" {
+		return call.Package + "." + call.Name
+	}
+
+	if call.Receiver == "" {
+		return call.Name
+	}
+
+	receiver := generator.current.Receiver
+
+	if receiver != "" && strings.Fields(receiver)[0] == call.Receiver {
+		return receiverType(receiver) + "." + call.Name
+	}
+
+	parameters, _ := extractParameters(generator.current.Parameters)
+
+	for _, parameter := range parameters {
+		if parameter.name == call.Receiver {
+			return receiverType(parameter.name+" "+parameter.typeName) + "." + call.Name
+		}
+	}
+
+	var found *Definition
+
+	for _, definition := range generator.definitions {
+		if definition.Receiver == "" || definition.Name != call.Name {
+			continue
+		}
+
+		if found != nil && found.ReturnsError != definition.ReturnsError {
+			generator.fail("%s calls the method %s on %s whose type is unknown, therefore it needs a unique name", generator.current.Name, call.Name, call.Receiver)
+			return ""
+		}
+
+		found = definition
+	}
+
+	if found == nil {
+		return ""
+	}
+
+	return found.key()
+}
+
+// fail records an error in the component that is being generated.
+// Only the first error is kept.
+func (generator *generator) fail(format string, arguments ...interface{}) {
+	if generator.err == nil {
+		generator.err = fmt.Errorf("component "+format, arguments...)
+	}
+}
+
+// loop returns the code added to the start of every loop iteration.
+func (generator *generator) loop() string {
+	if generator.compiler.Context {
+		return contextCheck
+	}
+
+	return ""
+}
+
+// Generates the code that writes the value of an expression.
+// Constant expressions are evaluated at compile time.
+func (generator *generator) expression(expression string, raw bool) string {
+	value, isConstant := evaluate(expression, generator.constants)
+
+	if isConstant {
+		if !raw {
+			value = html.EscapeString(value)
+		}
+
+		return writeString(value)
+	}
+
+	if raw {
+		return write(expression)
+	}
+
+	return writeEscaped(expression)
+}
+
+// Generates the code that writes the value of an expression returning a value and an error.
+// The error is returned from the stream function.
+func (generator *generator) fallible(expression string, raw bool) string {
+	if !generator.returnsError(generator.current.key()) {
+		generator.fail("%s uses ?= but doesn't return an error", generator.current.Name)
+		return ""
+	}
+
+	code := "{\n_v, _err := " + expression + "\nif _err != nil {\nreturn _err\n}\n"
+
+	if raw {
+		code += write("_v")
+	} else {
+		code += writeEscaped("_v")
+	}
+
+	return code + "}\n"
+}
+
+// contextCheck returns the error of a canceled context.
+const contextCheck = "if _err := ctx.Err(); _err != nil {\nreturn _err\n}\n"
+
+// tag returns the code for the tag and its attributes.
+func (generator *generator) tag(element *Element) string {
+	code := acquireStringsBuilder()
+
+	if element.Name == "html" {
+		code.WriteString(writeString("<!DOCTYPE html>"))
+	}
+
+	code.WriteString(writeString("<" + element.Name))
+
+	for _, attribute := range element.Attributes {
+		// Attributes without a value
+		if attribute.Value == "" {
+			code.WriteString(writeString(" " + attribute.Name))
+			continue
+		}
+
+		value, isConstant := evaluate(attribute.Value, generator.constants)
+
+		if isConstant {
+			// Attribute values are enclosed by apostrophes.
+			// Therefore we need to escape this character in string literals
+			// which can contain entities. Other values are escaped like dynamic values.
+			if isStringLiteral(attribute.Value) {
+				value = strings.Replace(value, "'", "&#39;", -1)
+			} else {
+				value = html.EscapeString(value)
+			}
+
+			code.WriteString(writeString(" " + attribute.Name + "='" + value + "'"))
+			continue
+		}
+
+		code.WriteString(writeString(" " + attribute.Name + "='"))
+		code.WriteString(writeEscaped(attribute.Value))
+		code.WriteString(writeString("'"))
+	}
+
+	code.WriteString(writeString(">"))
+	result := code.String()
+	pool.Put(code)
+	return result
+}
+
+// endTag returns the code for the end tag.
+func endTag(keyword string) string {
+	if !selfClosingTags[keyword] {
+		return writeString("</" + keyword + ">")
+	}
+
+	return ""
+}
+
+// Writes expression to the output.
+func write(expression string) string {
+	if strings.HasPrefix(expression, "'") {
+		color.Red("Strings must use \" instead of '")
+		return ""
+	}
+
+	return "_b.WriteString(" + expression + ")\n"
+}
+
+// Writes the HTML-escaped value of expression to the output.
+func writeEscaped(expression string) string {
+	return "writeEscaped(_b, " + expression + ")\n"
+}
+
+// Writes s interpreted as a string (not an expression) to the output.
+func writeString(s string) string {
+	return write(strconv.Quote(s))
+}
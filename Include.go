@@ -0,0 +1,181 @@
+package pixy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aerogo/codetree"
+	"github.com/akyoto/color"
+)
+
+// include is a file included in a component body.
+// It is replaced by the content of the file when the template is loaded.
+type include struct {
+	path     string
+	position Position
+}
+
+// includer loads templates together with the files they include.
+// Each template is included only once and cycles are reported as errors.
+type includer struct {
+	stack    []string
+	included map[string]bool
+}
+
+// loadTemplate parses a template and the files it includes.
+// Include paths are relative to the directory of the file
+// or to the working directory if the file is empty.
+// The macros of all templates are expanded in all components.
+func loadTemplate(reader io.Reader, file string) (*template, error) {
+	loader := &includer{included: map[string]bool{}}
+	parsed, err := loader.parse(reader, file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = ExpandMacros(parsed.definitions, parsed.macros)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// parse parses the template and adds the declarations of the included templates.
+func (loader *includer) parse(reader io.Reader, file string) (*template, error) {
+	code, err := ioutil.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := codetree.New(bytes.NewReader(code))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tree.Close()
+	parsed := parseTemplate(tree, newSource(file, tree, code))
+	directory := ""
+	absolute := ""
+
+	if file != "" {
+		directory = filepath.Dir(file)
+		absolute, err = filepath.Abs(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		loader.included[absolute] = true
+	}
+
+	for _, definition := range parsed.definitions {
+		err := loader.replace(definition.Children, directory)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	loader.stack = append(loader.stack, absolute)
+	defer func() { loader.stack = loader.stack[:len(loader.stack)-1] }()
+
+	for _, path := range parsed.includes {
+		path = filepath.Join(directory, path)
+		absolute, err := filepath.Abs(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for index, including := range loader.stack {
+			if including == absolute {
+				cycle := append(loader.stack[index:len(loader.stack):len(loader.stack)], absolute)
+				return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		// Templates included more than once are compiled once
+		if loader.included[absolute] {
+			continue
+		}
+
+		included, err := loader.file(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.definitions = append(parsed.definitions, included.definitions...)
+		parsed.declarations = append(parsed.declarations, included.declarations...)
+		parsed.imports = append(parsed.imports, included.imports...)
+		parsed.code = append(parsed.code, included.code...)
+
+		for tag, macro := range included.macros {
+			if parsed.macros[tag] == nil {
+				parsed.macros[tag] = macro
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// file parses the included template in the file.
+func (loader *includer) file(path string) (*template, error) {
+	reader, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't include %s: %v", path, err)
+	}
+
+	defer reader.Close()
+	return loader.parse(reader, path)
+}
+
+// replace replaces the files included in the nodes by their content.
+func (loader *includer) replace(nodes []Node, directory string) error {
+	return replaceNodes(nodes, func(node Node) (Node, error) {
+		included, isInclude := node.(*include)
+
+		if !isInclude {
+			return node, nil
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(directory, included.path))
+
+		if err != nil {
+			return nil, included.position.Errorf("can't include %s: %v", included.path, err)
+		}
+
+		return &Embed{
+			Expression: strconv.Quote(string(content)),
+			Position:   included.position,
+		}, nil
+	})
+}
+
+// parseInclude parses the quoted path of an include directive.
+// Invalid paths return an empty string.
+func parseInclude(line string) string {
+	quoted := strings.TrimSpace(line[len("include "):])
+	path, err := strconv.Unquote(quoted)
+
+	if err != nil || path == "" {
+		color.Yellow(line)
+		color.Red("Includes need a quoted file path.")
+		return ""
+	}
+
+	return path
+}
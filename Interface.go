@@ -1,6 +1,9 @@
 package pixy
 
-import "io"
+import (
+	"io"
+	"os"
+)
 
 // Compile compiles a Pixy template as a reader and returns a slice of components.
 func Compile(reader io.Reader) ([]*Component, error) {
@@ -21,3 +24,39 @@ func CompileBytes(src []byte) ([]*Component, error) {
 func CompileFile(fileIn string) ([]*Component, error) {
 	return DefaultCompiler.CompileFile(fileIn)
 }
+
+// CompileFiles compiles the Pixy templates of a package and returns the components of all of them.
+func CompileFiles(files ...string) ([]*Component, error) {
+	return DefaultCompiler.CompileFiles(files...)
+}
+
+// Parse parses a Pixy template and returns the component definitions.
+// Included files are relative to the working directory.
+func Parse(reader io.Reader) ([]*Definition, error) {
+	parsed, err := loadTemplate(reader, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.definitions, nil
+}
+
+// ParseFile parses a Pixy template read from a file and returns the component definitions.
+// Included files are relative to the directory of the template.
+func ParseFile(path string) ([]*Definition, error) {
+	reader, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+	parsed, err := loadTemplate(reader, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.definitions, nil
+}
@@ -0,0 +1,220 @@
+package pixy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akyoto/color"
+)
+
+// Macro replaces elements with a tag name by calls to a component.
+// The attributes of the element are passed to the parameters with the same name,
+// the shorthand classes to the Classes parameter and the text or the expression
+// of the element to the Content parameter.
+type Macro struct {
+	// Component is the name of the called component, e.g. "Button" or "ui.Button".
+	Component string
+
+	// Classes is the name of the parameter receiving the classes.
+	Classes string
+
+	// Content is the name of the parameter receiving the text or the expression.
+	Content string
+}
+
+// parseMacro parses a macro declaration like macro Button Button(label=content, kind=classes).
+// Invalid declarations return an empty tag name.
+func parseMacro(line string) (string, *Macro) {
+	declaration := strings.Fields(line[len("macro "):])
+
+	if len(declaration) == 0 {
+		color.Yellow(line)
+		color.Red("Macros need a tag name and a component.")
+		return "", nil
+	}
+
+	tag := declaration[0]
+	target := strings.TrimSpace(strings.Join(declaration[1:], " "))
+	macro := &Macro{Component: target}
+	open := strings.IndexByte(target, '(')
+
+	if open != -1 && strings.HasSuffix(target, ")") {
+		macro.Component = target[:open]
+
+		for _, mapping := range splitRaw(target[open+1 : len(target)-1]) {
+			equals := strings.IndexByte(mapping, '=')
+
+			if equals == -1 {
+				macro.Component = ""
+				break
+			}
+
+			parameter := strings.TrimSpace(mapping[:equals])
+
+			switch strings.TrimSpace(mapping[equals+1:]) {
+			case "content":
+				macro.Content = parameter
+			case "classes":
+				macro.Classes = parameter
+			default:
+				macro.Component = ""
+			}
+		}
+	}
+
+	if macro.Component == "" || strings.ContainsAny(macro.Component, " ()") {
+		color.Yellow(line)
+		color.Red("Macros map a tag name to a component like macro Button Button(label=content, kind=classes).")
+		return "", nil
+	}
+
+	return tag, macro
+}
+
+// ExpandMacros replaces the elements whose tag name has a macro by component calls.
+// Calls to the components in the definitions have positional arguments
+// and the other calls have named arguments.
+// The compiler expands the macros of the templates and of the Macros option.
+func ExpandMacros(definitions []*Definition, macros map[string]*Macro) error {
+	if len(macros) == 0 {
+		return nil
+	}
+
+	components := map[string]*Definition{}
+
+	for _, definition := range definitions {
+		if definition.Receiver == "" {
+			components[definition.Name] = definition
+		}
+	}
+
+	for _, definition := range definitions {
+		err := replaceNodes(definition.Children, func(node Node) (Node, error) {
+			element, isElement := node.(*Element)
+
+			if !isElement || macros[element.Name] == nil {
+				return node, nil
+			}
+
+			return macros[element.Name].call(element, components)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// call returns the component call replacing the element.
+func (macro *Macro) call(element *Element, components map[string]*Definition) (*Call, error) {
+	if len(element.Children) > 0 || element.Fallible {
+		return nil, element.Position.Errorf("macro %s can't have children or use ?=", element.Name)
+	}
+
+	var names []string
+	values := map[string]string{}
+
+	argument := func(parameter string, value string) error {
+		if !isIdentifier(parameter) {
+			return element.Position.Errorf("macro %s has no parameter for %s", element.Name, parameter)
+		}
+
+		names = append(names, parameter)
+		values[parameter] = value
+		return nil
+	}
+
+	for _, attribute := range element.Attributes {
+		parameter := attribute.Name
+		value := attribute.Value
+
+		if parameter == "class" && macro.Classes != "" {
+			parameter = macro.Classes
+		}
+
+		// Attributes without a value are true
+		if value == "" {
+			value = "true"
+		}
+
+		err := argument(parameter, value)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content := element.Expression
+
+	if content == "" && element.Text != "" {
+		content = strconv.Quote(element.Text)
+	}
+
+	if content != "" {
+		err := argument(macro.Content, content)
+
+		if err != nil {
+			return nil, element.Position.Errorf("macro %s has no parameter for the content", element.Name)
+		}
+	}
+
+	call := &Call{
+		Name:     macro.Component,
+		Position: element.Position,
+	}
+
+	dot := strings.IndexByte(macro.Component, '.')
+
+	if dot != -1 {
+		call.Package = macro.Component[:dot]
+		call.Name = macro.Component[dot+1:]
+	}
+
+	definition, isComponent := components[call.Name]
+
+	// Components in other templates or packages get named arguments
+	if call.Package != "" || !isComponent {
+		arguments := make([]string, len(names))
+
+		for index, name := range names {
+			arguments[index] = name + "=" + values[name]
+		}
+
+		call.Arguments = strings.Join(arguments, ", ")
+		return call, nil
+	}
+
+	var arguments []string
+	var parameters []string
+
+	if definition.Parameters != "" {
+		parameters = extractParameterNames(definition.Parameters)
+	}
+
+	for _, parameter := range parameters {
+		parameter = strings.TrimSuffix(parameter, "...")
+		value, exists := values[parameter]
+
+		if !exists {
+			value, exists = definition.Defaults[parameter]
+		}
+
+		if !exists {
+			return nil, element.Position.Errorf("macro %s is missing the parameter %s of %s", element.Name, parameter, call.Name)
+		}
+
+		arguments = append(arguments, value)
+		delete(values, parameter)
+	}
+
+	for _, name := range names {
+		if _, unused := values[name]; unused {
+			return nil, element.Position.Errorf("macro %s passes %s which is not a parameter of %s", element.Name, name, call.Name)
+		}
+	}
+
+	call.Arguments = strings.Join(arguments, ", ")
+	return call, nil
+}
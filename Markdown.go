@@ -0,0 +1,257 @@
+package pixy
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// markdownPunctuation contains the characters that can be escaped with a backslash.
+const markdownPunctuation = "\\`*_{}[]()#+-.!>"
+
+// markdown converts a subset of Markdown to HTML: headings, paragraphs, lists,
+// block quotes, fenced code blocks, horizontal rules, emphasis, code spans, links and images.
+// Empty lines separate the paragraphs and lists, other lines continue them.
+func markdown(text string) (string, error) {
+	output := strings.Builder{}
+	renderMarkdown(&output, strings.Split(strings.TrimSuffix(text, "\n"), "\n"))
+	return output.String(), nil
+}
+
+// renderMarkdown writes the HTML of the Markdown lines to the output.
+func renderMarkdown(output *strings.Builder, lines []string) {
+	var (
+		// block is the tag of the paragraph or list that is still open
+		block string
+		items []string
+	)
+
+	closeBlock := func() {
+		switch block {
+		case "p":
+			output.WriteString("<p>" + markdownInline(items[0]) + "</p>")
+		case "ul", "ol":
+			output.WriteString("<" + block + ">")
+
+			for _, item := range items {
+				output.WriteString("<li>" + markdownInline(item) + "</li>")
+			}
+
+			output.WriteString("</" + block + ">")
+		}
+
+		block = ""
+		items = items[:0]
+	}
+
+	for index := 0; index < len(lines); index++ {
+		line := lines[index]
+		trimmed := strings.TrimSpace(line)
+
+		// Empty lines end the paragraph or list
+		if trimmed == "" {
+			closeBlock()
+			continue
+		}
+
+		// Indented lines continue the previous paragraph or list item
+		if strings.HasPrefix(line, "\t") && block != "" {
+			items[len(items)-1] += "\n" + trimmed
+			continue
+		}
+
+		// Fenced code blocks
+		if strings.HasPrefix(trimmed, "```") {
+			closeBlock()
+			language := strings.TrimSpace(trimmed[len("```"):])
+			var code []string
+
+			for index++; index < len(lines) && strings.TrimSpace(lines[index]) != "```"; index++ {
+				code = append(code, lines[index])
+			}
+
+			if language != "" {
+				output.WriteString("<pre><code class='language-" + html.EscapeString(language) + "'>")
+			} else {
+				output.WriteString("<pre><code>")
+			}
+
+			output.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			output.WriteString("</code></pre>")
+			continue
+		}
+
+		// Headings
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+
+		if level >= 1 && level <= 6 && (level == len(trimmed) || trimmed[level] == ' ') {
+			closeBlock()
+			tag := "h" + strconv.Itoa(level)
+			output.WriteString("<" + tag + ">" + markdownInline(strings.TrimSpace(trimmed[level:])) + "</" + tag + ">")
+			continue
+		}
+
+		// Horizontal rules
+		if isMarkdownRule(trimmed) {
+			closeBlock()
+			output.WriteString("<hr>")
+			continue
+		}
+
+		// Block quotes
+		if strings.HasPrefix(trimmed, ">") {
+			closeBlock()
+			var quoted []string
+
+			for ; index < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[index]), ">"); index++ {
+				quote := strings.TrimSpace(lines[index])[1:]
+				quoted = append(quoted, strings.TrimPrefix(quote, " "))
+			}
+
+			index--
+			output.WriteString("<blockquote>")
+			renderMarkdown(output, quoted)
+			output.WriteString("</blockquote>")
+			continue
+		}
+
+		// List items
+		tag, item := markdownListItem(trimmed)
+
+		if tag != "" {
+			if block != tag {
+				closeBlock()
+				block = tag
+			}
+
+			items = append(items, item)
+			continue
+		}
+
+		// Other lines continue the paragraph or list item until an empty line
+		if block != "" {
+			items[len(items)-1] += "\n" + trimmed
+			continue
+		}
+
+		// Paragraphs
+		block = "p"
+		items = append(items, trimmed)
+	}
+
+	closeBlock()
+}
+
+// markdownListItem returns the list tag and the content of a list item.
+// The tag is empty if the line is not a list item.
+func markdownListItem(line string) (string, string) {
+	if len(line) >= 2 && strings.IndexByte("-*+", line[0]) != -1 && line[1] == ' ' {
+		return "ul", strings.TrimSpace(line[2:])
+	}
+
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+
+	if digits > 0 && strings.HasPrefix(line[digits:], ". ") {
+		return "ol", strings.TrimSpace(line[digits+2:])
+	}
+
+	return "", ""
+}
+
+// isMarkdownRule tells you whether the line is a horizontal rule like ---.
+func isMarkdownRule(line string) bool {
+	if len(line) < 3 || strings.IndexByte("-*_", line[0]) == -1 {
+		return false
+	}
+
+	return strings.Trim(line, line[:1]) == ""
+}
+
+// markdownInline returns the HTML of the inline elements in the text.
+func markdownInline(text string) string {
+	output := strings.Builder{}
+
+	for index := 0; index < len(text); {
+		char := text[index]
+		remaining := text[index:]
+
+		switch {
+		// Escaped characters
+		case char == '\\' && len(remaining) > 1 && strings.IndexByte(markdownPunctuation, remaining[1]) != -1:
+			output.WriteString(html.EscapeString(remaining[1:2]))
+			index += 2
+			continue
+
+		// Code spans
+		case char == '`':
+			end := strings.IndexByte(remaining[1:], '`')
+
+			if end != -1 {
+				output.WriteString("<code>" + html.EscapeString(remaining[1:end+1]) + "</code>")
+				index += end + 2
+				continue
+			}
+
+		// Emphasis, underscores only at the start of a word
+		case char == '*' || (char == '_' && (index == 0 || !isIdentifierByte(text[index-1]))):
+			delimiter := remaining[:1]
+			tag := "em"
+
+			if strings.HasPrefix(remaining, delimiter+delimiter) {
+				delimiter += delimiter
+				tag = "strong"
+			}
+
+			end := strings.Index(remaining[len(delimiter):], delimiter)
+			inner := ""
+
+			if end > 0 {
+				inner = remaining[len(delimiter) : len(delimiter)+end]
+			}
+
+			if strings.TrimSpace(inner) == inner && inner != "" {
+				output.WriteString("<" + tag + ">" + markdownInline(inner) + "</" + tag + ">")
+				index += end + 2*len(delimiter)
+				continue
+			}
+
+		// Links and images
+		case char == '[' || strings.HasPrefix(remaining, "!["):
+			image := char == '!'
+			start := strings.IndexByte(remaining, '[') + 1
+			middle := strings.Index(remaining, "](")
+
+			if middle == -1 {
+				break
+			}
+
+			end := strings.IndexByte(remaining[middle:], ')')
+
+			if end == -1 {
+				break
+			}
+
+			label := remaining[start:middle]
+
+			if strings.IndexByte(label, ']') != -1 {
+				break
+			}
+
+			url := html.EscapeString(remaining[middle+2 : middle+end])
+
+			if image {
+				output.WriteString("<img src='" + url + "' alt='" + html.EscapeString(label) + "'>")
+			} else {
+				output.WriteString("<a href='" + url + "'>" + markdownInline(label) + "</a>")
+			}
+
+			index += middle + end + 1
+			continue
+		}
+
+		output.WriteString(html.EscapeString(remaining[:1]))
+		index++
+	}
+
+	return output.String()
+}
@@ -0,0 +1,195 @@
+package pixy
+
+import "strings"
+
+// Node is a single node in the tree of a parsed component.
+// Every node has the Position of its line in the template.
+type Node interface{}
+
+// Definition is a parsed component definition.
+// Components defined as methods have a Receiver like "post *Post".
+// TypeParameters and Parameters contain the Go type parameter list and parameter list
+// without brackets and Defaults maps parameter names to their default values as Go expressions.
+type Definition struct {
+	Name           string
+	Receiver       string
+	TypeParameters string
+	Parameters     string
+	Defaults       map[string]string
+	ReturnsError   bool
+	Children       []Node
+	Position       Position
+}
+
+// key returns the name of the component that is unique within its package.
+// Methods are prefixed with the name of the receiver type, e.g. "Post.Card".
+func (definition *Definition) key() string {
+	if definition.Receiver == "" {
+		return definition.Name
+	}
+
+	return receiverType(definition.Receiver) + "." + definition.Name
+}
+
+// template contains the top-level declarations of a Pixy template:
+// the component definitions, the signatures of components in other packages,
+// the imported packages, the Go code of go blocks,
+// the paths of the included templates and the macros by tag name.
+type template struct {
+	definitions  []*Definition
+	declarations []*Definition
+	imports      []*packageImport
+	code         []string
+	includes     []string
+	macros       map[string]*Macro
+}
+
+// packageImport is a Go package imported by a template.
+// The name is the alias or the last element of the path.
+type packageImport struct {
+	name    string
+	path    string
+	aliased bool
+}
+
+// Element is an HTML element. Its content is either Text,
+// the value of the Go expression in Expression or nothing.
+// Raw values bypass HTML escaping and Fallible expressions return a value and an error.
+type Element struct {
+	Name       string
+	Attributes []*Attribute
+	Text       string
+	Expression string
+	Raw        bool
+	Fallible   bool
+	Children   []Node
+	Position   Position
+}
+
+// Attribute is an HTML attribute whose value is a Go expression.
+// Attributes without a value have an empty value.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Call is a call to another component. Calls to components defined as methods
+// have a Receiver expression, calls to components in other packages have the
+// Package name and TypeArguments contains the explicit type arguments of a generic component.
+type Call struct {
+	Receiver      string
+	Package       string
+	Name          string
+	TypeArguments string
+	Arguments     string
+	Position      Position
+}
+
+// NamedArguments returns the names and values of the arguments
+// if the call uses named arguments like Hello(person="World").
+func (call *Call) NamedArguments() (names []string, values []string) {
+	for _, argument := range splitRaw(call.Arguments) {
+		equals := strings.Index(argument, "=")
+
+		if equals == -1 || strings.HasPrefix(argument[equals:], "==") || !isIdentifier(strings.TrimSpace(argument[:equals])) {
+			return nil, nil
+		}
+
+		names = append(names, strings.TrimSpace(argument[:equals]))
+		values = append(values, strings.TrimSpace(argument[equals+1:]))
+	}
+
+	return names, values
+}
+
+// DynamicCall is a call to a component whose name is determined at runtime.
+// The first argument is the name of the component.
+type DynamicCall struct {
+	Arguments string
+	Position  Position
+}
+
+// Block is a Go flow control statement like if, else or for.
+type Block struct {
+	Statement string
+	Children  []Node
+	Position  Position
+}
+
+// Each iterates over the elements of a slice.
+type Each struct {
+	Iterator string
+	Slice    string
+	Reversed bool
+	Children []Node
+	Position Position
+}
+
+// Embed writes the result of a Go expression without escaping.
+type Embed struct {
+	Expression string
+	Position   Position
+}
+
+// Parallel renders the component calls it contains concurrently.
+type Parallel struct {
+	Children []Node
+	Position Position
+}
+
+// Deferred renders a placeholder in place of its children.
+// The children are rendered in the background and written at the end of the document.
+type Deferred struct {
+	Placeholder []Node
+	Children    []Node
+	Position    Position
+}
+
+// Fragment marks its children as a part of the component that can be rendered on its own.
+type Fragment struct {
+	Name     string
+	Children []Node
+	Position Position
+}
+
+// Flush sends the output written so far to the client.
+type Flush struct {
+	Position Position
+}
+
+// Filter is a block of text that the filter with the given name
+// transforms at compile time, e.g. :markdown.
+type Filter struct {
+	Name     string
+	Text     string
+	Position Position
+}
+
+// SetAttribute sets the value of an attribute, replacing an existing one with the same name.
+func (e *Element) SetAttribute(name string, value string) {
+	for _, attribute := range e.Attributes {
+		if attribute.Name == name {
+			attribute.Value = value
+			return
+		}
+	}
+
+	e.Attributes = append(e.Attributes, &Attribute{Name: name, Value: value})
+}
+
+// RemoveAttribute removes the attribute with the given name and returns its value.
+func (e *Element) RemoveAttribute(name string) string {
+	for index, attribute := range e.Attributes {
+		if attribute.Name == name {
+			e.Attributes = append(e.Attributes[:index], e.Attributes[index+1:]...)
+			return attribute.Value
+		}
+	}
+
+	return ""
+}
+
+// SelfClosing tells whether the element is written without an end tag.
+func (e *Element) SelfClosing() bool {
+	return selfClosingTags[e.Name]
+}
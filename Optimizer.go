@@ -2,6 +2,7 @@ package pixy
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -12,6 +13,8 @@ const (
 )
 
 // optimize combines multiple WriteString calls to one.
+// If the code does nothing but write a static string,
+// inlined contains that string as a quoted Go string.
 func optimize(code string) (optimizedCode string, inlined string) {
 	lines := strings.Split(code, "\n")
 	lastString := strings.Builder{}
@@ -21,15 +24,13 @@ func optimize(code string) (optimizedCode string, inlined string) {
 
 	for index, line := range lines {
 		// Find WriteString call
-		pos := strings.Index(line, writeStringCall)
-
-		if pos != -1 {
-			if line[pos+len(writeStringCall)] == '"' {
-				// Delete this line and save it in a buffer "lastString"
-				lastString.WriteString(line[pos+len(writeStringCall)+1 : len(line)-2])
-				lines[index] = ""
-				continue
-			}
+		literal, isLiteral := writtenLiteral(line)
+
+		if isLiteral {
+			// Delete this line and save it in a buffer "lastString"
+			lastString.WriteString(literal[1 : len(literal)-1])
+			lines[index] = ""
+			continue
 		}
 
 		if lastString.Len() > 0 {
@@ -37,9 +38,47 @@ func optimize(code string) (optimizedCode string, inlined string) {
 			lastString.Reset()
 		}
 
-		lineCount++
+		if strings.TrimSpace(line) != "" {
+			lineCount++
+		}
 	}
 
 	compact := compactCode.ReplaceAllString(strings.Join(lines, "\n"), "\n")
-	return compact, inlined
+
+	if lineCount == 0 {
+		trimmed := strings.TrimSpace(compact)
+
+		if trimmed == "" {
+			return compact, "\"\""
+		}
+
+		literal, _ := writtenLiteral(trimmed)
+		return compact, literal
+	}
+
+	return compact, ""
+}
+
+// writtenLiteral returns the quoted string if the line
+// is a WriteString call with a single string literal.
+func writtenLiteral(line string) (string, bool) {
+	pos := strings.Index(line, writeStringCall)
+
+	if pos == -1 || !strings.HasSuffix(line, ")") {
+		return "", false
+	}
+
+	literal := line[pos+len(writeStringCall) : len(line)-1]
+
+	if !strings.HasPrefix(literal, "\"") {
+		return "", false
+	}
+
+	_, err := strconv.Unquote(literal)
+
+	if err != nil {
+		return "", false
+	}
+
+	return literal, true
 }
@@ -1,147 +1,548 @@
 package pixy
 
 import (
-	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/aerogo/codetree"
 	"github.com/akyoto/color"
 	"github.com/akyoto/ignore"
 )
 
-// Compiles the children of a Pixy CodeTree.
-func compileChildren(node *codetree.CodeTree) string {
-	output := ""
+// scope contains the names that can qualify a component call:
+// the variables in scope and the imported packages.
+// The source is used to find the positions of the nodes.
+type scope struct {
+	variables []string
+	packages  []string
+	source    *source
+}
 
-	for _, child := range node.Children {
-		code := strings.TrimSpace(compileNode(child))
+// with returns a scope for a nested block that declares the given variables.
+func (names *scope) with(variables []string) *scope {
+	return &scope{
+		variables: append(names.variables[:len(names.variables):len(names.variables)], variables...),
+		packages:  names.packages,
+		source:    names.source,
+	}
+}
 
-		if len(code) > 0 {
-			if strings.HasPrefix(code, "else {") || strings.HasPrefix(code, "else if ") {
-				output = strings.TrimRight(output, "\n") + code + "\n"
-			} else {
-				output += code + "\n"
-			}
+// source is a template file together with the line numbers of its nodes.
+type source struct {
+	file  string
+	lines map[*codetree.CodeTree]int
+	text  []string
+}
+
+// newSource returns the source of the tree parsed from the code.
+// The lines are numbered like codetree reads them: lines containing only tabs are skipped.
+func newSource(file string, tree *codetree.CodeTree, code []byte) *source {
+	var nodes []*codetree.CodeTree
+
+	var collect func(*codetree.CodeTree)
+	collect = func(parent *codetree.CodeTree) {
+		for _, child := range parent.Children {
+			nodes = append(nodes, child)
+			collect(child)
+		}
+	}
+
+	collect(tree)
+	lines := make(map[*codetree.CodeTree]int, len(nodes))
+	text := strings.Split(string(code), "\n")
+
+	for number, line := range text {
+		if len(lines) == len(nodes) {
+			break
+		}
+
+		if strings.Trim(strings.TrimSuffix(line, "\r"), "\t") == "" {
+			continue
 		}
+
+		lines[nodes[len(lines)]] = number + 1
 	}
 
-	return output
+	return &source{
+		file:  file,
+		lines: lines,
+		text:  text,
+	}
+}
+
+// position returns the position of the node.
+func (source *source) position(node *codetree.CodeTree) Position {
+	return Position{
+		File:   source.file,
+		Line:   source.lines[node],
+		Column: node.Indent + 1,
+	}
 }
 
-// Writes expression to the output.
-func write(expression string) string {
-	if strings.HasPrefix(expression, "'") {
-		color.Red("Strings must use \" instead of '")
+// code returns the lines below the node with their indentation relative to the node.
+// Unlike the nodes of the tree, the lines include the empty lines between them.
+func (source *source) code(node *codetree.CodeTree) string {
+	last := node
+
+	for len(last.Children) > 0 {
+		last = last.Children[len(last.Children)-1]
+	}
+
+	if last == node {
 		return ""
 	}
 
-	return "_b.WriteString(" + expression + ")\n"
-}
+	code := strings.Builder{}
+
+	for _, line := range source.text[source.lines[node]:source.lines[last]] {
+		line = strings.TrimSuffix(line, "\r")
+
+		for indent := 0; indent <= node.Indent && strings.HasPrefix(line, "\t"); indent++ {
+			line = line[1:]
+		}
+
+		code.WriteString(strings.TrimRight(line, "\t"))
+		code.WriteByte('\n')
+	}
 
-// Writes s interpreted as a string (not an expression) to the output.
-func writeString(s string) string {
-	return write("\"" + s + "\"")
+	return code.String()
 }
 
-// isString
-func isString(code string) bool {
-	// TODO: Fix this
-	return strings.HasPrefix(code, "\"") && strings.HasSuffix(code, "\"")
+// parseTemplate parses the component definitions, the imports, the go blocks, the includes and the macros on the top level of a Pixy CodeTree.
+func parseTemplate(tree *codetree.CodeTree, file *source) *template {
+	parsed := &template{
+		definitions: []*Definition{},
+		imports:     parseImports(tree),
+		macros:      map[string]*Macro{},
+	}
+
+	packages := make([]string, len(parsed.imports))
+
+	for index, imported := range parsed.imports {
+		packages[index] = imported.name
+	}
+
+	for _, node := range tree.Children {
+		// Ignore comments and imports
+		if strings.HasPrefix(node.Line, "//") || strings.HasPrefix(node.Line, "import ") {
+			continue
+		}
+
+		// Included templates add their declarations
+		if strings.HasPrefix(node.Line, "include ") {
+			path := parseInclude(node.Line)
+
+			if path != "" {
+				parsed.includes = append(parsed.includes, path)
+			}
+
+			continue
+		}
+
+		// Macros replace elements by component calls
+		if strings.HasPrefix(node.Line, "macro ") {
+			tag, macro := parseMacro(node.Line)
+
+			if macro != nil {
+				parsed.macros[tag] = macro
+			}
+
+			continue
+		}
+
+		// Go code is copied to the generated code
+		if node.Line == "go" {
+			parsed.code = append(parsed.code, file.code(node))
+			continue
+		}
+
+		// Disallow tags on the top level
+		if !strings.HasPrefix(node.Line, "component ") {
+			color.Yellow(node.Line)
+			color.Red("Only 'component', 'import', 'include', 'macro' and 'go' declarations are allowed on the top level.")
+			continue
+		}
+
+		// Signature contains the signature of the component without the preceding keyword.
+		signature := node.Line[len("component "):]
+
+		// Components can return errors
+		returnsError := strings.HasSuffix(signature, " error")
+		signature = strings.TrimSuffix(signature, " error")
+
+		// Components can be methods
+		receiver := ""
+
+		if strings.HasPrefix(signature, "(") {
+			end := strings.Index(signature, ")")
+
+			if end == -1 {
+				color.Yellow(signature)
+				color.Red("The receiver of the component is missing a closing parenthesis.")
+				continue
+			}
+
+			receiver = strings.TrimSpace(signature[1:end])
+			signature = strings.TrimSpace(signature[end+1:])
+		}
+
+		// Any signature that ends with empty parentheses should be rewritten to not include them.
+		if strings.HasSuffix(signature, "()") {
+			color.Yellow(signature)
+			color.Red("Components without definition should not include parentheses in the definition.")
+		}
+
+		// Add parentheses to empty parameter lists
+		if !strings.HasSuffix(signature, ")") {
+			signature += "()"
+		}
+
+		// Get the necessary info from the component signature
+		name, typeParameters, rest := splitTypeParameters(signature)
+
+		if !strings.HasPrefix(rest, "(") {
+			color.Yellow(signature)
+			color.Red("The type parameters of the component are missing a closing bracket.")
+			continue
+		}
+
+		parameters, defaults := extractDefaults(rest[1 : len(rest)-1])
+
+		// Components in other packages are declared without a body
+		// so that calls know whether they return an error
+		if strings.Contains(name, ".") {
+			if len(node.Children) > 0 || receiver != "" {
+				color.Yellow(node.Line)
+				color.Red("Components in other packages can only be declared with their signature.")
+				continue
+			}
+
+			parsed.declarations = append(parsed.declarations, &Definition{
+				Name:         name,
+				Parameters:   parameters,
+				Defaults:     defaults,
+				ReturnsError: returnsError,
+				Position:     file.position(node),
+			})
+
+			continue
+		}
+
+		// The receiver and the parameters are the variables in scope
+		var variables []string
+
+		if receiver != "" {
+			variables = append(variables, strings.Fields(receiver)[0])
+		}
+
+		if parameters != "" {
+			for _, parameter := range extractParameterNames(parameters) {
+				variables = append(variables, strings.TrimSuffix(parameter, "..."))
+			}
+		}
+
+		parsed.definitions = append(parsed.definitions, &Definition{
+			Name:           name,
+			Receiver:       receiver,
+			TypeParameters: typeParameters,
+			Parameters:     parameters,
+			Defaults:       defaults,
+			ReturnsError:   returnsError,
+			Children:       parseChildren(node, &scope{variables: variables, packages: packages, source: file}),
+			Position:       file.position(node),
+		})
+	}
+
+	return parsed
 }
 
-// tag returns the code for the tag and its attributes.
-func tag(keyword string, attributes map[string]string) string {
-	code := acquireStringsBuilder()
+// parseImports parses the imports of Go packages on the top level of a Pixy CodeTree,
+// e.g. import "example.com/app/ui" or import views "example.com/app/ui".
+func parseImports(tree *codetree.CodeTree) []*packageImport {
+	var imports []*packageImport
+
+	for _, node := range tree.Children {
+		if !strings.HasPrefix(node.Line, "import ") {
+			continue
+		}
+
+		declaration := strings.TrimSpace(node.Line[len("import "):])
+		quote := strings.Index(declaration, "\"")
+
+		if quote == -1 || !strings.HasSuffix(declaration, "\"") || quote == len(declaration)-1 {
+			color.Yellow(node.Line)
+			color.Red("Imports need a quoted package path.")
+			continue
+		}
 
-	if keyword == "html" {
-		code.WriteString(writeString("<!DOCTYPE html>"))
+		path := declaration[quote+1 : len(declaration)-1]
+		name := strings.TrimSpace(declaration[:quote])
+
+		if name == "" {
+			name = path[strings.LastIndex(path, "/")+1:]
+		}
+
+		imports = append(imports, &packageImport{
+			name:    name,
+			path:    path,
+			aliased: declaration[:quote] != "",
+		})
 	}
 
-	numAttributes := len(attributes)
+	return imports
+}
+
+// parseFilter parses a filter block like :markdown. The text can start on the same line.
+func parseFilter(node *codetree.CodeTree, file *source) *Filter {
+	name := node.Line[1:]
+	text := ""
+	space := strings.IndexByte(name, ' ')
 
-	if numAttributes == 0 {
-		code.WriteString(writeString("<" + keyword + ">"))
-		return code.String()
+	if space != -1 {
+		text = strings.TrimSpace(name[space+1:]) + "\n"
+		name = name[:space]
 	}
 
-	code.WriteString(writeString("<" + keyword + " "))
-	count := 1
+	return &Filter{
+		Name: name,
+		Text: text + file.code(node),
+	}
+}
 
-	// Attributes
-	for key, value := range attributes {
-		// Attributes without a value
-		if value == "" {
-			code.WriteString(writeString(key))
+// Parses the children of a Pixy CodeTree.
+func parseChildren(node *codetree.CodeTree, names *scope) []Node {
+	var children []Node
 
-			if count != numAttributes {
-				code.WriteString(writeString(" "))
-			}
+	for _, child := range node.Children {
+		parsed := parseNode(child, names)
+
+		if parsed != nil {
+			children = append(children, parsed)
+		}
+	}
+
+	return children
+}
 
-			count++
+// Parses a deferred block. The children of the placeholder child
+// are rendered while the other children are deferred.
+func parseDeferred(node *codetree.CodeTree, names *scope) *Deferred {
+	block := &Deferred{}
+
+	for _, child := range node.Children {
+		if child.Line == "placeholder" {
+			block.Placeholder = append(block.Placeholder, parseChildren(child, names)...)
 			continue
 		}
 
-		code.WriteString(writeString(key + "='"))
+		parsed := parseNode(child, names)
+
+		if parsed != nil {
+			block.Children = append(block.Children, parsed)
+		}
+	}
+
+	return block
+}
+
+// parseCall parses a call to a component in the same package.
+// Lines without the syntax of a call like Button.primary Save return nil.
+func parseCall(line string) *Call {
+	name, typeArguments, rest := splitTypeParameters(line)
+
+	if !isIdentifier(name) {
+		return nil
+	}
+
+	if rest == "" {
+		return &Call{Name: name, TypeArguments: typeArguments}
+	}
+
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return nil
+	}
+
+	return &Call{
+		Name:          name,
+		TypeArguments: typeArguments,
+		Arguments:     rest[1 : len(rest)-1],
+	}
+}
+
+// parseQualifiedCall returns the call if the line calls a component defined as a method,
+// like post.Card or post.Author.Card("small"), and the receiver is a variable in scope,
+// or a component in an imported package like ui.Button("Save").
+// Otherwise the line is an element like p.Card with a class, unless the call is explicit.
+func parseQualifiedCall(line string, names *scope, explicit bool) *Call {
+	name, typeArguments, rest := splitTypeParameters(line)
+
+	if rest != "" && (!strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")")) {
+		return nil
+	}
+
+	dot := strings.LastIndex(name, ".")
+
+	if dot == -1 {
+		return nil
+	}
+
+	method := name[dot+1:]
+	first, _ := utf8.DecodeRuneInString(method)
+
+	if !isIdentifier(method) || (!explicit && !unicode.IsUpper(first)) {
+		return nil
+	}
+
+	receiver := name[:dot]
+
+	for _, part := range strings.Split(receiver, ".") {
+		if !isIdentifier(part) {
+			return nil
+		}
+	}
+
+	call := &Call{
+		Name:          method,
+		TypeArguments: typeArguments,
+	}
+
+	if rest != "" {
+		call.Arguments = rest[1 : len(rest)-1]
+	}
+
+	root := strings.Split(receiver, ".")[0]
 
-		if isString(value) {
-			// Attribute values are enclosed by apostrophes.
-			// Therefore we need to escape this character in the attribute value.
-			code.WriteString(write(strings.Replace(value, "'", "&#39;", -1)))
-		} else {
-			code.WriteString(write("html.EscapeString(fmt.Sprint(" + value + "))"))
+	// Variables shadow packages
+	for _, variable := range names.variables {
+		if strings.TrimSpace(variable) == root {
+			call.Receiver = receiver
+			return call
 		}
+	}
 
-		if count == numAttributes {
-			code.WriteString(writeString("'"))
-		} else {
-			code.WriteString(writeString("' "))
+	for _, name := range names.packages {
+		if name == receiver {
+			call.Package = receiver
+			return call
 		}
+	}
 
-		count++
+	if explicit {
+		call.Receiver = receiver
+		return call
 	}
 
-	code.WriteString(writeString(">"))
-	result := code.String()
-	pool.Put(code)
-	return result
+	return nil
 }
 
-// endTag returns the code for the end tag.
-func endTag(keyword string) string {
-	if !selfClosingTags[keyword] {
-		return writeString("</" + keyword + ">")
+// Parses a single codetree.CodeTree and sets the position of the node.
+func parseNode(node *codetree.CodeTree, names *scope) Node {
+	parsed := parseLine(node, names)
+	position := names.source.position(node)
+
+	switch parsed := parsed.(type) {
+	case *Element:
+		parsed.Position = position
+	case *Call:
+		parsed.Position = position
+	case *DynamicCall:
+		parsed.Position = position
+	case *Block:
+		parsed.Position = position
+	case *Each:
+		parsed.Position = position
+	case *Embed:
+		parsed.Position = position
+	case *Parallel:
+		parsed.Position = position
+	case *Deferred:
+		parsed.Position = position
+	case *Fragment:
+		parsed.Position = position
+	case *Flush:
+		parsed.Position = position
+	case *Filter:
+		parsed.Position = position
+	case *include:
+		parsed.position = position
 	}
 
-	return ""
+	return parsed
 }
 
-// Compiles a single codetree.CodeTree.
-func compileNode(node *codetree.CodeTree) string {
+// parseLine parses a line of a component and its children.
+func parseLine(node *codetree.CodeTree, names *scope) Node {
 	var keyword string
 
 	if node.Line[0] == '#' || node.Line[0] == '.' {
 		node.Line = "div" + node.Line
 	}
 
+	// Calls to components selected at runtime
+	if strings.HasPrefix(node.Line, "+Dynamic(") && strings.HasSuffix(node.Line, ")") {
+		return &DynamicCall{Arguments: node.Line[len("+Dynamic(") : len(node.Line)-1]}
+	}
+
+	// Files included in the output
+	if strings.HasPrefix(node.Line, "include ") {
+		path := parseInclude(node.Line)
+
+		if path == "" {
+			return nil
+		}
+
+		return &include{path: path}
+	}
+
+	// Text transformed by a filter
+	if strings.HasPrefix(node.Line, ":") {
+		return parseFilter(node, names.source)
+	}
+
+	// Explicit calls like +button("Save") can call unexported components
+	if strings.HasPrefix(node.Line, "+") {
+		line := node.Line[1:]
+		call := parseQualifiedCall(line, names, true)
+
+		if call == nil {
+			call = parseCall(line)
+		}
+
+		if call == nil {
+			color.Yellow(node.Line)
+			color.Red("Explicit calls need the syntax +Name(arguments).")
+			return nil
+		}
+
+		return call
+	}
+
+	// Calls to components defined as methods or in other packages
+	call := parseQualifiedCall(node.Line, names, false)
+
+	if call != nil {
+		return call
+	}
+
 	for i, letter := range node.Line {
-		// Function calls
+		// Function calls, other lines are elements that can be macros
 		if i == 0 && unicode.IsLetter(letter) && unicode.IsUpper(letter) {
-			if !strings.HasSuffix(node.Line, ")") {
-				node.Line += "()"
-			}
+			call := parseCall(node.Line)
 
-			return "stream" + strings.Replace(node.Line, "(", "(_b, ", 1)
+			if call != nil {
+				return call
+			}
 		}
 
 		// Go external function call embeds
 		if i == 2 && node.Line[:3] == "go:" {
-			return write(node.Line[3:])
+			return &Embed{Expression: node.Line[3:]}
 		}
 
 		// Comments
 		if i == 1 && node.Line[0] == '/' && node.Line[1] == '/' {
-			return ""
+			return nil
 		}
 
 		// Find keyword
@@ -155,39 +556,60 @@ func compileNode(node *codetree.CodeTree) string {
 		keyword = node.Line
 	}
 
+	// Flush the output
+	if node.Line == "flush" {
+		return &Flush{}
+	}
+
+	// Concurrent rendering
+	if node.Line == "parallel" {
+		return &Parallel{Children: parseChildren(node, names)}
+	}
+
+	// Out-of-order streaming
+	if node.Line == "deferred" {
+		return parseDeferred(node, names)
+	}
+
+	// Fragments that can be rendered on their own
+	if keyword == "fragment" && node.Line != keyword {
+		return &Fragment{
+			Name:     strings.TrimSpace(node.Line[len(keyword):]),
+			Children: parseChildren(node, names),
+		}
+	}
+
 	// Flow control
 	if keyword == "if" || keyword == "else" || keyword == "for" {
-		return node.Line + " {\n" + compileChildren(node) + "}"
+		return &Block{
+			Statement: node.Line,
+			Children:  parseChildren(node, names.with(declaredNames(node.Line))),
+		}
 	}
 
 	// Each is just syntax sugar
 	if keyword == "each" {
-		// Each with reversed order
-		if strings.HasSuffix(node.Line, " reversed") {
-			line := strings.TrimSuffix(node.Line, " reversed")
-			inIndex := strings.Index(line, " in ")
-			sliceName := line[inIndex+len(" in "):]
-			iteratorName := line[len("each "):inIndex]
+		line := strings.TrimSuffix(node.Line, " reversed")
+		inIndex := strings.Index(line, " in ")
 
-			return fmt.Sprintf("{\n_s := %s\nfor _i := len(_s)-1; _i >= 0; _i-- {\n%s := _s[_i]\n%s}\n}", sliceName, iteratorName, compileChildren(node))
-		}
+		iterator := line[len("each "):inIndex]
 
-		// TODO: This is a just quick prototype implementation and not correct at all
-		return strings.Replace(strings.Replace(node.Line, "each", "for _, ", 1), " in ", " := range ", 1) + " {\n" + compileChildren(node) + "}"
+		return &Each{
+			Iterator: iterator,
+			Slice:    line[inIndex+len(" in "):],
+			Reversed: len(line) != len(node.Line),
+			Children: parseChildren(node, names.with(strings.Split(iterator, ","))),
+		}
 	}
 
-	var contents string
-	attributes := make(map[string]string)
+	tag := &Element{Name: keyword}
 
 	// No contents?
 	if node.Line == keyword {
-		code := tag(keyword, attributes)
-		code += compileChildren(node)
-		code += endTag(keyword)
-		return code
+		tag.Children = parseChildren(node, names)
+		return tag
 	}
 
-	escapeInput := true
 	cursor := len(keyword)
 
 	expect := func(expected byte, callback func(int, string)) bool {
@@ -212,48 +634,29 @@ func compileNode(node *codetree.CodeTree) string {
 		return false
 	}
 
-	// ID
-	expect('#', func(start int, remaining string) {
-		endFound := false
-
+	// readName reads a name consisting of letters, digits and dashes.
+	readName := func(start int, remaining string) string {
 		for index, letter := range remaining {
 			if !unicode.IsLetter(letter) && !unicode.IsDigit(letter) && letter != '-' {
 				cursor += index
-				id := node.Line[start:cursor]
-				attributes["id"] = "\"" + id + "\""
-				endFound = true
-				break
+				return node.Line[start:cursor]
 			}
 		}
 
-		if !endFound {
-			cursor = len(node.Line)
-			id := node.Line[start:cursor]
-			attributes["id"] = "\"" + id + "\""
-		}
+		cursor = len(node.Line)
+		return node.Line[start:cursor]
+	}
+
+	// ID
+	expect('#', func(start int, remaining string) {
+		tag.SetAttribute("id", "\""+readName(start, remaining)+"\"")
 	})
 
 	// Classes
 	var classes []string
 
 	for expect('.', func(start int, remaining string) {
-		endFound := false
-
-		for index, letter := range remaining {
-			if !unicode.IsLetter(letter) && !unicode.IsDigit(letter) && letter != '-' {
-				cursor += index
-				name := node.Line[start:cursor]
-				classes = append(classes, name)
-				endFound = true
-				break
-			}
-		}
-
-		if !endFound {
-			cursor = len(node.Line)
-			name := node.Line[start:cursor]
-			classes = append(classes, name)
-		}
+		classes = append(classes, readName(start, remaining))
 	}) {
 		// Empty loop
 	}
@@ -272,7 +675,6 @@ func compileNode(node *codetree.CodeTree) string {
 			if !unicode.IsLetter(letter) && !unicode.IsDigit(letter) && letter != '-' {
 				cursor += index
 				attributeName = node.Line[start:cursor]
-				// fmt.Println("NAME", attributeName)
 				break
 			}
 		}
@@ -300,7 +702,7 @@ func compileNode(node *codetree.CodeTree) string {
 						attributeValue = ""
 					}
 
-					attributes[attributeName] = attributeValue
+					tag.SetAttribute(attributeName, attributeValue)
 					cursor++
 
 					return letter == ','
@@ -308,7 +710,7 @@ func compileNode(node *codetree.CodeTree) string {
 			}
 		} else if char == ',' || char == ')' {
 			// Attribute without a value
-			attributes[attributeName] = ""
+			tag.SetAttribute(attributeName, "")
 			cursor++
 
 			if char == ',' {
@@ -327,48 +729,50 @@ func compileNode(node *codetree.CodeTree) string {
 	})
 
 	if len(classes) > 0 {
-		existingClassList := attributes["class"]
+		classList := "\"" + strings.Join(classes, " ") + "\""
+		existingClassList := tag.RemoveAttribute("class")
 
 		if existingClassList != "" {
-			attributes["class"] = "\"" + strings.Join(classes, " ") + " \" + " + existingClassList
-		} else {
-			attributes["class"] = "\"" + strings.Join(classes, " ") + "\""
+			classList = "\"" + strings.Join(classes, " ") + " \" + " + existingClassList
+		}
+
+		// Shorthand classes are placed after the shorthand ID
+		position := 0
+
+		if node.Line[len(keyword)] == '#' {
+			position = 1
 		}
+
+		tag.Attributes = append(tag.Attributes, nil)
+		copy(tag.Attributes[position+1:], tag.Attributes[position:])
+		tag.Attributes[position] = &Attribute{Name: "class", Value: classList}
 	}
 
 	if cursor < len(node.Line) {
+		// Expressions returning an error
+		if strings.HasPrefix(node.Line[cursor:], "?=") || strings.HasPrefix(node.Line[cursor:], "?!=") {
+			tag.Fallible = true
+			cursor++
+		}
+
 		// Bypass HTML escaping
-		if node.Line[cursor] == '!' {
-			escapeInput = false
+		if cursor < len(node.Line) && node.Line[cursor] == '!' {
+			tag.Raw = true
 			cursor++
 		}
 
 		// Expressions
-		if node.Line[cursor] == '=' {
-			contents = strings.TrimLeft(node.Line[cursor+1:], " ")
-
-			code := tag(keyword, attributes)
-
-			if escapeInput {
-				code += write("html.EscapeString(fmt.Sprint(" + contents + "))")
-			} else {
-				code += write(contents)
-			}
-
-			code += compileChildren(node)
-			code += endTag(keyword)
-			return code
+		if cursor < len(node.Line) && node.Line[cursor] == '=' {
+			tag.Expression = strings.TrimLeft(node.Line[cursor+1:], " ")
+			tag.Children = parseChildren(node, names)
+			return tag
 		}
 
-		contents = node.Line[cursor+1:]
-		contents = strings.Replace(contents, "\"", "\\\"", -1)
-	} else {
-		contents = ""
+		if cursor < len(node.Line) {
+			tag.Text = node.Line[cursor+1:]
+		}
 	}
 
-	code := tag(keyword, attributes)
-	code += writeString(contents)
-	code += compileChildren(node)
-	code += endTag(keyword)
-	return code
+	tag.Children = parseChildren(node, names)
+	return tag
 }
@@ -0,0 +1,95 @@
+package pixy
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// compileProps generates the props struct of a component
+// and the functions rendering the component with it.
+func (compiler *Compiler) compileProps(definition *Definition, returnsError bool) string {
+	parameters, ok := extractParameters(definition.Parameters)
+
+	if !ok {
+		return ""
+	}
+
+	target := targets[compiler.Target]
+	propsName := definition.Name + "Props"
+	fields := ""
+	arguments := make([]string, 0, len(parameters))
+	defaults := ""
+
+	for _, parameter := range parameters {
+		fieldName := exported(parameter.name)
+		typeName := parameter.typeName
+		argument := "props." + fieldName
+
+		if value, hasDefault := definition.Defaults[parameter.name]; hasDefault {
+			defaults += fieldName + ": " + value + ", "
+		}
+
+		// Variadic parameters are stored in a slice
+		if strings.HasPrefix(typeName, "...") {
+			typeName = "[]" + typeName[len("..."):]
+			argument += "..."
+		}
+
+		fields += "\t" + fieldName + " " + typeName + "\n"
+		arguments = append(arguments, argument)
+	}
+
+	// Generic components have generic props
+	typeParameters := typeArguments(definition.TypeParameters)
+	typeNames := ""
+
+	if typeParameters != "" {
+		typeNames = typeArguments(strings.Join(extractParameterNames(definition.TypeParameters), ", "))
+	}
+
+	writer := target.parameter[:strings.Index(target.parameter, " ")]
+	functionParameters := "props " + propsName + typeNames
+	streamParameters := target.parameter + ", " + functionParameters
+	streamArguments := append([]string{writer}, arguments...)
+	returnType := " string"
+	streamReturnType := ""
+	streamReturn := ""
+
+	// The context is passed on as the first argument
+	if compiler.Context {
+		functionParameters = "ctx context.Context, " + functionParameters
+		streamParameters = "ctx context.Context, " + streamParameters
+		arguments = append([]string{"ctx"}, arguments...)
+		streamArguments = append([]string{"ctx"}, streamArguments...)
+	}
+
+	if returnsError {
+		returnType = " (string, error)"
+		streamReturnType = " error"
+		streamReturn = "return "
+	}
+
+	code := "// " + propsName + " contains the parameters of the " + definition.Name + " component.\n"
+	code += "type " + propsName + typeParameters + " struct {\n" + fields + "}\n\n"
+	code += "// " + definition.Name + "With renders the " + definition.Name + " component with the given props.\n"
+	code += "func " + definition.Name + "With" + typeParameters + "(" + functionParameters + ")" + returnType + " {\n"
+	code += "\treturn " + definition.Name + typeNames + "(" + strings.Join(arguments, ", ") + ")\n}\n\n"
+	code += "func " + compiler.streamName(definition.Name+"With") + typeParameters + "(" + streamParameters + ")" + streamReturnType + " {\n"
+	code += "\t" + streamReturn + compiler.streamName(definition.Name) + typeNames + "(" + strings.Join(streamArguments, ", ") + ")\n}"
+
+	// The props don't know which fields were set, therefore the defaults
+	// are filled in by a constructor instead of the render functions
+	if defaults != "" {
+		constructor := "New" + propsName
+
+		if !ast.IsExported(definition.Name) {
+			constructor = "new" + exported(propsName)
+		}
+
+		code += "\n\n// " + constructor + " returns the props of the " + definition.Name + " component with the default values.\n"
+		code += "func " + constructor + typeParameters + "() " + propsName + typeNames + " {\n"
+		code += "\treturn " + propsName + typeNames + "{" + strings.TrimSuffix(defaults, ", ") + "}\n}"
+	}
+
+	return code
+}
@@ -0,0 +1,248 @@
+package pixy
+
+import (
+	"go/ast"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetRegistry returns the file header and a registry of the given components
+// that describes their parameters and renders them by name at runtime.
+// The registry is required by templates using dynamic calls.
+// Generic components and methods are not part of the registry
+// and unexported components can only be rendered by dynamic calls.
+func (compiler *Compiler) GetRegistry(components []*Component) string {
+	target := targets[compiler.Target]
+	sorted := make([]*Component, 0, len(components))
+
+	// Generic components can't be called without type arguments
+	// and methods can't be called without a receiver.
+	for _, component := range components {
+		if component.TypeParameters == "" && component.Receiver == "" {
+			sorted = append(sorted, component)
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	code := strings.Builder{}
+	code.WriteString(compiler.GetFileHeader())
+	code.WriteString(registryTypes)
+	code.WriteString("\nfunc init() {\n")
+
+	for index, component := range sorted {
+		parameters, _ := extractParameters(component.Parameters)
+
+		if index > 0 {
+			code.WriteByte('\n')
+		}
+
+		registry := "Registry"
+
+		if !ast.IsExported(component.Name) {
+			registry = "privateRegistry"
+		}
+
+		code.WriteString("\t" + registry + "[")
+		code.WriteString(strconv.Quote(component.Name))
+		code.WriteString("] = &ComponentInfo{\n\t\tName: ")
+		code.WriteString(strconv.Quote(component.Name))
+		code.WriteString(",\n\t\tParameters: []ParameterInfo{\n")
+
+		for _, parameter := range parameters {
+			// Variadic parameters receive their arguments as a slice
+			typeName := parameter.typeName
+
+			if strings.HasPrefix(typeName, "...") {
+				typeName = "[]" + typeName[len("..."):]
+			}
+
+			code.WriteString("\t\t\t{Name: ")
+			code.WriteString(strconv.Quote(parameter.name))
+			code.WriteString(", Type: reflect.TypeOf((*")
+			code.WriteString(typeName)
+			code.WriteString(")(nil)).Elem()")
+
+			value, hasDefault := component.Defaults[parameter.name]
+
+			if hasDefault {
+				code.WriteString(", Default: reflect.ValueOf(func() ")
+				code.WriteString(typeName)
+				code.WriteString(" { return ")
+				code.WriteString(value)
+				code.WriteString(" }())")
+			}
+
+			code.WriteString("},\n")
+		}
+
+		code.WriteString("\t\t},\n")
+
+		if isVariadic(parameters) {
+			code.WriteString("\t\tVariadic: true,\n")
+		}
+
+		if component.ReturnsError {
+			code.WriteString("\t\tReturnsError: true,\n")
+		}
+
+		code.WriteString("\t\tfunction: reflect.ValueOf(")
+		code.WriteString(component.Name)
+		code.WriteString("),\n\t\tstream: reflect.ValueOf(")
+		code.WriteString(compiler.streamName(component.Name))
+		code.WriteString("),\n\t}\n")
+	}
+
+	code.WriteString("}\n")
+	render := registryRender
+
+	// The context is passed on as the first argument
+	if compiler.Context {
+		render = strings.Replace(render, "(name string, args ...interface{})", "(ctx context.Context, name string, args ...interface{})", -1)
+		render = strings.Replace(render, "(_b *strings.Builder, name string", "(ctx context.Context, _b *strings.Builder, name string", 1)
+		render = strings.Replace(render, "call(values)", "call(append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, values...))", 1)
+		render = strings.Replace(render, "[]reflect.Value{reflect.ValueOf(_b)}", "[]reflect.Value{reflect.ValueOf(&ctx).Elem(), reflect.ValueOf(_b)}", 1)
+	}
+
+	code.WriteString(strings.Replace(render, "*strings.Builder", target.writer, -1))
+	return code.String()
+}
+
+// SaveRegistry writes the registry of the given components to a file.
+func (compiler *Compiler) SaveRegistry(filePath string, components []*Component) error {
+	return ioutil.WriteFile(filePath, []byte(compiler.GetRegistry(components)), 0644)
+}
+
+const registryTypes = `// ComponentInfo describes a component and its parameters.
+type ComponentInfo struct {
+	Name         string
+	Parameters   []ParameterInfo
+	Variadic     bool
+	ReturnsError bool
+	function     reflect.Value
+	stream       reflect.Value
+}
+
+// ParameterInfo describes a parameter of a component.
+// Default is the zero Value for parameters without a default value.
+type ParameterInfo struct {
+	Name    string
+	Type    reflect.Type
+	Default reflect.Value
+}
+
+// Registry maps the names of the components to their descriptions.
+var Registry = map[string]*ComponentInfo{}
+
+// privateRegistry contains the unexported components,
+// which can only be rendered by dynamic calls in the package.
+var privateRegistry = map[string]*ComponentInfo{}
+`
+
+const registryRender = `
+// Render renders the component with the given name.
+// The arguments are checked against the parameters of the component
+// and variadic parameters receive their arguments as a slice.
+func Render(name string, args ...interface{}) (string, error) {
+	component, values, err := registryArguments(name, args, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	call := component.function.Call
+
+	if component.Variadic {
+		call = component.function.CallSlice
+	}
+
+	results := call(values)
+
+	if len(results) == 2 && !results[1].IsNil() {
+		return "", results[1].Interface().(error)
+	}
+
+	return results[0].String(), nil
+}
+
+// streamDynamic writes the component with the given name to the output.
+func streamDynamic(_b *strings.Builder, name string, args ...interface{}) error {
+	component, values, err := registryArguments(name, args, true)
+
+	if err != nil {
+		return err
+	}
+
+	call := component.stream.Call
+
+	if component.Variadic {
+		call = component.stream.CallSlice
+	}
+
+	results := call(append([]reflect.Value{reflect.ValueOf(_b)}, values...))
+
+	if len(results) == 1 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
+
+	return nil
+}
+
+// registryArguments returns the component with the given name
+// and the arguments as values of the parameter types.
+// Unexported components are only found if private is true.
+func registryArguments(name string, args []interface{}, private bool) (*ComponentInfo, []reflect.Value, error) {
+	component, exists := Registry[name]
+
+	if !exists && private {
+		component, exists = privateRegistry[name]
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("unknown component %s", name)
+	}
+
+	if len(args) > len(component.Parameters) {
+		return nil, nil, fmt.Errorf("component %s needs %d arguments but got %d", name, len(component.Parameters), len(args))
+	}
+
+	values := make([]reflect.Value, 0, len(component.Parameters))
+
+	for index, arg := range args {
+		parameter := component.Parameters[index]
+
+		if arg == nil {
+			switch parameter.Type.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+				values = append(values, reflect.Zero(parameter.Type))
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("component %s: cannot use nil as %s in parameter %s", name, parameter.Type, parameter.Name)
+		}
+
+		value := reflect.ValueOf(arg)
+
+		if !value.Type().AssignableTo(parameter.Type) {
+			return nil, nil, fmt.Errorf("component %s: cannot use %s as %s in parameter %s", name, value.Type(), parameter.Type, parameter.Name)
+		}
+
+		values = append(values, value)
+	}
+
+	// Omitted trailing arguments use the default values of the parameters
+	for len(values) < len(component.Parameters) && component.Parameters[len(values)].Default.IsValid() {
+		values = append(values, component.Parameters[len(values)].Default)
+	}
+
+	if len(values) != len(component.Parameters) {
+		return nil, nil, fmt.Errorf("component %s needs %d arguments but got %d", name, len(component.Parameters), len(args))
+	}
+
+	return component, values, nil
+}
+`
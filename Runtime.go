@@ -0,0 +1,491 @@
+package pixy
+
+import (
+	"sort"
+	"strings"
+)
+
+// utilities returns the imports and utility functions for the generated code.
+func utilities(target *targetCode) string {
+	imports := []string{"fmt", "strconv", "strings", "sync", "sync/atomic"}
+	code := stringsBuilderPool + renderParallel
+	deferredCode := deferredQueue
+
+	if target.buffer == "BytesBuffer" {
+		deferredCode = strings.Replace(deferredCode, "StringsBuilder", "BytesBuffer", -1)
+		deferredCode = strings.Replace(deferredCode, "*strings.Builder", "*bytes.Buffer", -1)
+	}
+
+	if target.buffer == "BytesBuffer" {
+		imports = append(imports, "bytes")
+		code += bytesBufferPool
+	}
+
+	if target.buffered {
+		imports = append(imports, "bufio", "io")
+		code += bufioWriterPool
+	}
+
+	if target.writer == "pixy.Writer" {
+		imports = append(imports, "github.com/aerogo/pixy")
+	}
+
+	if target.prologue != "" {
+		imports = append(imports, "io")
+		code += ioWriter
+	}
+
+	if target.flushable {
+		code += flusher(target)
+	}
+
+	code += deferredCode + deferredFlush(target)
+	code += sizeHints + fragmentErrors
+	code += strings.Replace(escape, "*strings.Builder", target.writer, -1)
+	return importDeclaration(imports) + code
+}
+
+// importDeclaration returns the import declaration for the given packages.
+func importDeclaration(imports []string) string {
+	sort.Strings(imports)
+	declaration := "\nimport (\n"
+	previous := ""
+
+	for _, path := range imports {
+		if path == previous {
+			continue
+		}
+
+		declaration += "\t\"" + path + "\"\n"
+		previous = path
+	}
+
+	return declaration + ")\n"
+}
+
+const stringsBuilderPool = `
+var _pool = sync.Pool{
+	New: func() interface{} {
+		return &strings.Builder{}
+	},
+}
+
+func acquireStringsBuilder() *strings.Builder {
+	return _pool.Get().(*strings.Builder)
+}
+
+// releaseStringsBuilder returns the builder to the pool.
+// The string returned by builder.String() shares the buffer of the builder,
+// therefore it must be called after the string has been retrieved.
+// Reset hands the buffer over to that string, so pooled builders are always
+// empty and can't keep large buffers alive no matter how much they grew.
+func releaseStringsBuilder(builder *strings.Builder) {
+	builder.Reset()
+	_pool.Put(builder)
+}
+`
+
+const renderParallel = `
+// renderParallel calls the render functions concurrently and returns their outputs in order.
+// At most limit functions run at the same time unless limit is zero.
+// The first error in order is returned and a panic is re-raised in the calling goroutine.
+func renderParallel(limit int, render ...func() (string, error)) ([]string, error) {
+	outputs := make([]string, len(render))
+	errs := make([]error, len(render))
+	panics := make([]interface{}, len(render))
+	wg := sync.WaitGroup{}
+	wg.Add(len(render))
+
+	var semaphore chan struct{}
+
+	if limit > 0 {
+		semaphore = make(chan struct{}, limit)
+	}
+
+	for index, function := range render {
+		if semaphore != nil {
+			semaphore <- struct{}{}
+		}
+
+		go func(index int, function func() (string, error)) {
+			defer wg.Done()
+
+			defer func() {
+				panics[index] = recover()
+
+				if semaphore != nil {
+					<-semaphore
+				}
+			}()
+
+			outputs[index], errs[index] = function()
+		}(index, function)
+	}
+
+	wg.Wait()
+
+	for _, value := range panics {
+		if value != nil {
+			panic(value)
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return outputs, nil
+}
+`
+
+const deferredQueue = `
+// deferredFragment is the content of a deferred block that is rendered in the background.
+type deferredFragment struct {
+	id     string
+	done   chan struct{}
+	output string
+	err    error
+	panic  interface{}
+}
+
+// deferredRender contains the deferred blocks queued while a component writes to a writer.
+type deferredRender struct {
+	fragments []*deferredFragment
+}
+
+var (
+	_deferredID    int64
+	_deferredMutex sync.Mutex
+	_deferred      = map[interface{}]*deferredRender{}
+)
+
+// beginDeferred starts queueing the deferred blocks written to the writer.
+// It returns nil if a component further up is already doing so,
+// in which case that component owns the queued blocks and writes them.
+func beginDeferred(writer interface{}) *deferredRender {
+	_deferredMutex.Lock()
+	defer _deferredMutex.Unlock()
+
+	if _deferred[writer] != nil {
+		return nil
+	}
+
+	render := &deferredRender{}
+	_deferred[writer] = render
+	return render
+}
+
+// endDeferred stops queueing the deferred blocks of the render that was started with beginDeferred.
+// Blocks that haven't been written are discarded so that they never end up in the output
+// of another render that gets the same writer from a pool.
+func endDeferred(writer interface{}, render *deferredRender) {
+	if render == nil {
+		return
+	}
+
+	_deferredMutex.Lock()
+	delete(_deferred, writer)
+	_deferredMutex.Unlock()
+}
+
+// deferContent starts rendering the content of a deferred block in the background
+// and queues it until flushDeferred is called for the same writer.
+// It returns the id of the element containing the placeholder.
+func deferContent(writer interface{}, render func(*strings.Builder) error) string {
+	fragment := &deferredFragment{
+		id:   strconv.FormatInt(atomic.AddInt64(&_deferredID, 1), 10),
+		done: make(chan struct{}),
+	}
+
+	_deferredMutex.Lock()
+	queue := _deferred[writer]
+	queue.fragments = append(queue.fragments, fragment)
+	_deferredMutex.Unlock()
+
+	go func() {
+		defer close(fragment.done)
+
+		defer func() {
+			fragment.panic = recover()
+		}()
+
+		buffer := acquireStringsBuilder()
+		fragment.err = render(buffer)
+		fragment.output = buffer.String()
+		releaseStringsBuilder(buffer)
+	}()
+
+	return fragment.id
+}
+`
+
+// deferredFlush returns the functions writing the deferred content for the target.
+// Targets that can be flushed send the rest of the document to the client
+// before waiting for the deferred content.
+func deferredFlush(target *targetCode) string {
+	flushOutput := ""
+
+	if target.flushable {
+		flushOutput = `
+	flush(_b)
+`
+	}
+
+	return strings.Replace(`
+// flushDeferred waits for the deferred blocks queued for the writer and writes their content
+// in template elements, each followed by a script that replaces the placeholder.
+// It returns the first error of the deferred blocks and re-raises their panics.
+func flushDeferred(_b *strings.Builder) error {
+	_deferredMutex.Lock()
+	var fragments []*deferredFragment
+
+	if render := _deferred[_b]; render != nil {
+		fragments = render.fragments
+		render.fragments = nil
+	}
+
+	_deferredMutex.Unlock()
+
+	if len(fragments) == 0 {
+		return nil
+	}
+`+flushOutput+`
+	var err error
+
+	for _, fragment := range fragments {
+		<-fragment.done
+
+		if fragment.panic != nil {
+			panic(fragment.panic)
+		}
+
+		if fragment.err != nil {
+			if err == nil {
+				err = fragment.err
+			}
+
+			continue
+		}
+
+		_b.WriteString("<template id='pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("-content'>")
+		_b.WriteString(fragment.output)
+		_b.WriteString("</template><script>(function(){var p=document.getElementById('pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("'),t=document.getElementById('pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("-content');p.replaceWith(t.content);t.remove()})()</script>")
+	}
+
+	return err
+}
+
+// finishDeferred writes the deferred blocks that are still queued at the end of the render
+// if the render was started by the calling component, e.g. because it has no body element.
+func finishDeferred(_b *strings.Builder, render *deferredRender) error {
+	if render == nil {
+		return nil
+	}
+
+	return flushDeferred(_b)
+}
+`, "*strings.Builder", target.writer, -1)
+}
+
+const bytesBufferPool = `
+// maxPooledBufferSize is the maximum capacity of buffers that are returned to the pool.
+const maxPooledBufferSize = 64 * 1024
+
+var _bufferPool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+func acquireBytesBuffer() *bytes.Buffer {
+	return _bufferPool.Get().(*bytes.Buffer)
+}
+
+// releaseBytesBuffer returns the buffer to the pool.
+// buffer.String() copies the contents, so the buffer can be re-used
+// unless it grew too large to be kept around.
+func releaseBytesBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buffer.Reset()
+	_bufferPool.Put(buffer)
+}
+`
+
+const bufioWriterPool = `
+var _bufioPool = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewWriter(nil)
+	},
+}
+
+func acquireBufioWriter(writer io.Writer) *bufio.Writer {
+	bufferedWriter := _bufioPool.Get().(*bufio.Writer)
+	bufferedWriter.Reset(writer)
+	return bufferedWriter
+}
+
+// releaseBufioWriter flushes the buffered data and returns the writer to the pool.
+func releaseBufioWriter(bufferedWriter *bufio.Writer) {
+	bufferedWriter.Flush()
+	bufferedWriter.Reset(nil)
+	_bufioPool.Put(bufferedWriter)
+}
+`
+
+const ioWriter = `
+// toWriter returns the io.Writer as a pixy.Writer, wrapping it if necessary.
+func toWriter(writer io.Writer) pixy.Writer {
+	if pixyWriter, ok := writer.(pixy.Writer); ok {
+		return pixyWriter
+	}
+
+	return &ioWriter{Writer: writer}
+}
+
+// ioWriter adds the methods of pixy.Writer to an io.Writer.
+type ioWriter struct {
+	io.Writer
+}
+
+// WriteString writes a string to the underlying writer.
+func (writer *ioWriter) WriteString(s string) (int, error) {
+	return io.WriteString(writer.Writer, s)
+}
+
+// WriteByte writes a single byte to the underlying writer.
+func (writer *ioWriter) WriteByte(c byte) error {
+	_, err := writer.Writer.Write([]byte{c})
+	return err
+}
+`
+
+// flusher returns the flush function for targets that can be flushed.
+func flusher(target *targetCode) string {
+	unwrap := ""
+
+	if target.prologue != "" {
+		unwrap = `
+	if wrapper, ok := writer.(*ioWriter); ok {
+		output = wrapper.Writer
+	}
+`
+	}
+
+	return `
+// flush sends the output written so far to the client
+// if the writer supports it, e.g. an http.Flusher.
+func flush(writer pixy.Writer) {
+	var output interface{} = writer
+` + unwrap + `
+	switch output := output.(type) {
+	case interface{ Flush() error }:
+		output.Flush()
+	case interface{ Flush() }:
+		output.Flush()
+	}
+}
+`
+}
+
+const sizeHints = `
+// sizeHint returns the current size hint for the output of a component.
+func sizeHint(hint *int64) int {
+	return int(atomic.LoadInt64(hint))
+}
+
+// updateSizeHint moves the size hint of a component towards the actual output size
+// using an exponentially weighted moving average.
+func updateSizeHint(hint *int64, size int) {
+	previous := atomic.LoadInt64(hint)
+	atomic.StoreInt64(hint, previous+(int64(size)-previous)/8)
+}
+`
+
+const fragmentErrors = `
+// errUnknownFragment returns the error for a fragment name that a component doesn't have.
+func errUnknownFragment(name string) error {
+	return fmt.Errorf("unknown fragment %q", name)
+}
+`
+
+const escape = `
+// writeEscaped writes the HTML-escaped representation of value to the builder.
+// Strings, numbers and booleans are written without calling fmt.Sprint.
+func writeEscaped(_b *strings.Builder, value interface{}) {
+	var buffer [64]byte
+
+	switch value := value.(type) {
+	case string:
+		writeEscapedString(_b, value)
+	case int:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int8:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int16:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int32:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int64:
+		_b.Write(strconv.AppendInt(buffer[:0], value, 10))
+	case uint:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint8:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint16:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint32:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint64:
+		_b.Write(strconv.AppendUint(buffer[:0], value, 10))
+	case float32:
+		_b.Write(strconv.AppendFloat(buffer[:0], float64(value), 'g', -1, 32))
+	case float64:
+		_b.Write(strconv.AppendFloat(buffer[:0], value, 'g', -1, 64))
+	case bool:
+		_b.Write(strconv.AppendBool(buffer[:0], value))
+	default:
+		writeEscapedString(_b, fmt.Sprint(value))
+	}
+}
+
+// writeEscapedString writes s to the builder, escaping the same characters as html.EscapeString.
+func writeEscapedString(_b *strings.Builder, s string) {
+	last := 0
+
+	for i := 0; i < len(s); i++ {
+		var escaped string
+
+		switch s[i] {
+		case '&':
+			escaped = "&amp;"
+		case '\'':
+			escaped = "&#39;"
+		case '<':
+			escaped = "&lt;"
+		case '>':
+			escaped = "&gt;"
+		case '"':
+			escaped = "&#34;"
+		default:
+			continue
+		}
+
+		_b.WriteString(s[last:i])
+		_b.WriteString(escaped)
+		last = i + 1
+	}
+
+	_b.WriteString(s[last:])
+}
+`
@@ -0,0 +1,77 @@
+package pixy
+
+// Target is the type of output that generated stream functions write to.
+type Target int
+
+const (
+	// TargetStringsBuilder generates stream functions writing to a *strings.Builder.
+	TargetStringsBuilder Target = iota
+
+	// TargetWriter generates stream functions writing to a pixy.Writer.
+	TargetWriter
+
+	// TargetBytesBuffer generates stream functions writing to a *bytes.Buffer.
+	TargetBytesBuffer
+
+	// TargetBufioWriter generates stream functions writing to a *bufio.Writer.
+	// The output can't be flushed because the underlying writer is unknown.
+	TargetBufioWriter
+
+	// TargetIOWriter generates stream functions writing to an io.Writer.
+	// This allows HTTP handlers to stream directly into the response.
+	TargetIOWriter
+)
+
+// targetCode contains the generated code that depends on the target.
+type targetCode struct {
+	// writer is the type of _b in the generated code.
+	writer string
+
+	// parameter is the first parameter of stream functions.
+	parameter string
+
+	// prologue is added to the start of stream functions.
+	prologue string
+
+	// buffer is the pooled in-memory buffer used by the normal functions,
+	// either "StringsBuilder" or "BytesBuffer".
+	buffer string
+
+	// buffered tells whether the normal functions wrap the buffer in a *bufio.Writer.
+	buffered bool
+
+	// flushable tells whether the output can be flushed.
+	flushable bool
+}
+
+var targets = map[Target]*targetCode{
+	TargetStringsBuilder: {
+		writer:    "*strings.Builder",
+		parameter: "_b *strings.Builder",
+		buffer:    "StringsBuilder",
+	},
+	TargetWriter: {
+		writer:    "pixy.Writer",
+		parameter: "_b pixy.Writer",
+		buffer:    "StringsBuilder",
+		flushable: true,
+	},
+	TargetBytesBuffer: {
+		writer:    "*bytes.Buffer",
+		parameter: "_b *bytes.Buffer",
+		buffer:    "BytesBuffer",
+	},
+	TargetBufioWriter: {
+		writer:    "*bufio.Writer",
+		parameter: "_b *bufio.Writer",
+		buffer:    "StringsBuilder",
+		buffered:  true,
+	},
+	TargetIOWriter: {
+		writer:    "pixy.Writer",
+		parameter: "_w io.Writer",
+		prologue:  "_b := toWriter(_w)\n",
+		buffer:    "StringsBuilder",
+		flushable: true,
+	},
+}
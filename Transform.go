@@ -0,0 +1,125 @@
+package pixy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Transform changes a parsed component before its code is generated,
+// e.g. to add attributes to elements. Errors should be created with
+// the Errorf method of the position of the node that caused them.
+type Transform func(definition *Definition) error
+
+// Position is the location of a node in a template.
+// Lines and columns start at 1, every tab of the indentation counts as one column.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String returns the position as file:line:column.
+// The file is omitted for templates that were not read from a file.
+func (position Position) String() string {
+	location := strconv.Itoa(position.Line) + ":" + strconv.Itoa(position.Column)
+
+	if position.File == "" {
+		return location
+	}
+
+	return position.File + ":" + location
+}
+
+// Errorf returns an error at the position.
+func (position Position) Errorf(format string, arguments ...interface{}) error {
+	return &Error{
+		Position: position,
+		Message:  fmt.Sprintf(format, arguments...),
+	}
+}
+
+// Error is an error at a position in a template.
+type Error struct {
+	Position Position
+	Message  string
+}
+
+// Error returns the position and the message.
+func (err *Error) Error() string {
+	return err.Position.String() + ": " + err.Message
+}
+
+// Transform applies the transforms to the component in order.
+// Errors without a position get the position of the component.
+func (definition *Definition) Transform(transforms ...Transform) error {
+	for _, transform := range transforms {
+		err := transform(definition)
+
+		if err == nil {
+			continue
+		}
+
+		var positioned *Error
+
+		if !errors.As(err, &positioned) {
+			err = definition.Position.Errorf("component %s: %v", definition.Name, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Walk calls visit for every node and its descendants in the order they appear in the template.
+// Nodes are visited before their children, therefore visit can change the children.
+func Walk(nodes []Node, visit func(node Node) error) error {
+	return replaceNodes(nodes, func(node Node) (Node, error) {
+		return node, visit(node)
+	})
+}
+
+// replaceNodes replaces every node and its descendants by the result of replace.
+// The children of a node are replaced after the node.
+func replaceNodes(nodes []Node, replace func(node Node) (Node, error)) error {
+	for index, node := range nodes {
+		replaced, err := replace(node)
+
+		if err != nil {
+			return err
+		}
+
+		nodes[index] = replaced
+
+		switch replaced := replaced.(type) {
+		case *Element:
+			err = replaceNodes(replaced.Children, replace)
+
+		case *Block:
+			err = replaceNodes(replaced.Children, replace)
+
+		case *Each:
+			err = replaceNodes(replaced.Children, replace)
+
+		case *Parallel:
+			err = replaceNodes(replaced.Children, replace)
+
+		case *Fragment:
+			err = replaceNodes(replaced.Children, replace)
+
+		case *Deferred:
+			err = replaceNodes(replaced.Placeholder, replace)
+
+			if err == nil {
+				err = replaceNodes(replaced.Children, replace)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,23 @@
 package pixy
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/akyoto/ignore"
 )
 
+// parameter is a single parameter of a component.
+type parameter struct {
+	name     string
+	typeName string
+}
+
 // extractParameterNames deletes the type information from a comma-separated list of parameters.
 func extractParameterNames(definition string) []string {
 	definitions := strings.Split(definition, ",")
@@ -17,8 +31,361 @@ func extractParameterNames(definition string) []string {
 			continue
 		}
 
+		// Variadic parameters are forwarded as a slice
+		if strings.HasPrefix(strings.TrimSpace(definition[space:]), "...") {
+			definitions[index] = definition[:space] + "..."
+			continue
+		}
+
 		definitions[index] = definition[:space]
 	}
 
 	return definitions
 }
+
+// splitTypeParameters splits a signature like "Table[T any](rows []T)" into the name,
+// the type parameters within the brackets and the rest starting with the parameters.
+func splitTypeParameters(signature string) (name string, typeParameters string, rest string) {
+	end := strings.IndexAny(signature, "[(")
+
+	if end == -1 {
+		return signature, "", ""
+	}
+
+	if signature[end] == '(' {
+		return signature[:end], "", signature[end:]
+	}
+
+	depth := 0
+
+	for index := end; index < len(signature); index++ {
+		switch signature[index] {
+		case '[':
+			depth++
+
+		case ']':
+			depth--
+
+			if depth == 0 {
+				return signature[:end], signature[end+1 : index], signature[index+1:]
+			}
+		}
+	}
+
+	return signature, "", ""
+}
+
+// receiverType returns the name of the type in a receiver like "post *Post".
+func receiverType(receiver string) string {
+	typeName := receiver
+	space := strings.Index(receiver, " ")
+
+	if space != -1 {
+		typeName = receiver[space+1:]
+	}
+
+	name, _, _ := splitTypeParameters(strings.TrimPrefix(strings.TrimSpace(typeName), "*"))
+	return name
+}
+
+// typeArguments returns the bracketed list of type arguments or type parameter names
+// that follows the name of a generic function.
+func typeArguments(list string) string {
+	if list == "" {
+		return ""
+	}
+
+	return "[" + list + "]"
+}
+
+// extractDefaults removes the default values like `kind string = "primary"`
+// from a comma-separated list of parameters and returns them by parameter name.
+func extractDefaults(definition string) (string, map[string]string) {
+	if !strings.Contains(definition, "=") {
+		return definition, nil
+	}
+
+	definitions := splitRaw(definition)
+	defaults := map[string]string{}
+
+	for index, definition := range definitions {
+		equal := strings.Index(definition, "=")
+
+		if equal <= 0 {
+			continue
+		}
+
+		definitions[index] = strings.TrimSpace(definition[:equal])
+		name := strings.Fields(definitions[index])[0]
+		defaults[name] = strings.TrimSpace(definition[equal+1:])
+	}
+
+	return strings.Join(definitions, ", "), defaults
+}
+
+// referencedVariable returns the first of the variables that the Go expression refers to
+// or an empty string if it doesn't refer to any of them.
+// Field names in selectors and struct literals are not variables.
+func referencedVariable(expression string, variables []string) string {
+	tree, err := parser.ParseExpr(expression)
+
+	if err != nil {
+		return ""
+	}
+
+	fields := map[*ast.Ident]bool{}
+	referenced := ""
+
+	// Parents are visited before their children, so fields are known before they're visited
+	ast.Inspect(tree, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			fields[node.Sel] = true
+
+		case *ast.KeyValueExpr:
+			if key, isIdentifier := node.Key.(*ast.Ident); isIdentifier {
+				fields[key] = true
+			}
+
+		case *ast.Ident:
+			if referenced == "" && !fields[node] && contains(variables, node.Name) {
+				referenced = node.Name
+			}
+		}
+
+		return referenced == ""
+	})
+
+	return referenced
+}
+
+// isVariadic tells you whether the last parameter in the list is variadic.
+func isVariadic(parameters []*parameter) bool {
+	return len(parameters) > 0 && strings.HasPrefix(parameters[len(parameters)-1].typeName, "...")
+}
+
+// extractParameters returns the names and types in a comma-separated list of parameters.
+// Parameters sharing a type like "a, b string" are split into separate parameters.
+func extractParameters(definition string) ([]*parameter, bool) {
+	source := "func(" + definition + ")"
+	expression, err := parser.ParseExpr(source)
+
+	if err != nil {
+		return nil, false
+	}
+
+	var parameters []*parameter
+
+	for _, field := range expression.(*ast.FuncType).Params.List {
+		typeName := source[field.Type.Pos()-1 : field.Type.End()-1]
+
+		for _, name := range field.Names {
+			parameters = append(parameters, &parameter{
+				name:     name.Name,
+				typeName: typeName,
+			})
+		}
+	}
+
+	return parameters, true
+}
+
+// basicTypes contains the predeclared types that constants can have.
+var basicTypes = map[string]bool{
+	"bool":       true,
+	"byte":       true,
+	"complex64":  true,
+	"complex128": true,
+	"float32":    true,
+	"float64":    true,
+	"int":        true,
+	"int8":       true,
+	"int16":      true,
+	"int32":      true,
+	"int64":      true,
+	"rune":       true,
+	"string":     true,
+	"uint":       true,
+	"uint8":      true,
+	"uint16":     true,
+	"uint32":     true,
+	"uint64":     true,
+	"uintptr":    true,
+}
+
+// splitArguments splits the comma-separated arguments of a function call.
+func splitArguments(arguments string) []string {
+	if strings.TrimSpace(arguments) == "" {
+		return nil
+	}
+
+	source := "f(" + arguments + ")"
+	expression, err := parser.ParseExpr(source)
+
+	if err != nil {
+		return nil
+	}
+
+	call := expression.(*ast.CallExpr)
+	split := make([]string, 0, len(call.Args))
+
+	for _, argument := range call.Args {
+		split = append(split, source[argument.Pos()-1:argument.End()-1])
+	}
+
+	return split
+}
+
+// splitRaw splits comma-separated arguments that aren't necessarily valid Go code.
+// Commas within strings and brackets don't separate arguments.
+func splitRaw(arguments string) []string {
+	if strings.TrimSpace(arguments) == "" {
+		return nil
+	}
+
+	var split []string
+	reader := ignore.Reader{}
+	start := 0
+
+	for index, letter := range arguments {
+		if reader.CanIgnore(letter) {
+			continue
+		}
+
+		if letter == ',' {
+			split = append(split, strings.TrimSpace(arguments[start:index]))
+			start = index + 1
+		}
+	}
+
+	return append(split, strings.TrimSpace(arguments[start:]))
+}
+
+// isIdentifier tells you whether the string is a valid Go identifier.
+func isIdentifier(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierByte(name[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contains tells you whether the list contains the string.
+func contains(list []string, s string) bool {
+	for _, element := range list {
+		if element == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exported returns the name with an uppercase first letter.
+func exported(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// declaredNames returns the names of the variables declared in a flow control statement.
+func declaredNames(statement string) []string {
+	definition := strings.Index(statement, ":=")
+
+	if definition == -1 {
+		return nil
+	}
+
+	names := statement[:definition]
+	names = strings.TrimPrefix(names, "else ")
+	names = strings.TrimPrefix(names, "if ")
+	names = strings.TrimPrefix(names, "for ")
+	return strings.Split(names, ",")
+}
+
+// importDeclarations returns the import declarations of the packages referenced in the code.
+func importDeclarations(code string, imports []*packageImport) string {
+	declarations := ""
+
+	for _, imported := range imports {
+		if !referencesPackage(code, imported.name) {
+			continue
+		}
+
+		declarations += "import "
+
+		if imported.aliased {
+			declarations += imported.name + " "
+		}
+
+		declarations += strconv.Quote(imported.path) + "\n"
+	}
+
+	return declarations
+}
+
+// referencesPackage tells you whether the code contains a qualified identifier of the package.
+func referencesPackage(code string, name string) bool {
+	qualifier := name + "."
+
+	for offset := 0; ; {
+		index := strings.Index(code[offset:], qualifier)
+
+		if index == -1 {
+			return false
+		}
+
+		start := offset + index
+
+		if start == 0 || (!isIdentifierByte(code[start-1]) && code[start-1] != '.') {
+			return true
+		}
+
+		offset = start + len(qualifier)
+	}
+}
+
+// references tells you whether the Go statements refer to the identifier.
+// Words in string literals, field names and selectors are not references.
+// Code that can't be parsed is assumed to refer to it.
+func references(code string, identifier string) bool {
+	tree, err := parser.ParseFile(token.NewFileSet(), "", "package pixy\nfunc _() {\n"+code+"\n}", 0)
+
+	if err != nil {
+		return true
+	}
+
+	fields := map[*ast.Ident]bool{}
+	referenced := false
+
+	ast.Inspect(tree.Decls[0], func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			fields[node.Sel] = true
+
+		case *ast.KeyValueExpr:
+			if key, isIdentifier := node.Key.(*ast.Ident); isIdentifier {
+				fields[key] = true
+			}
+
+		case *ast.Ident:
+			if !fields[node] && node.Name == identifier {
+				referenced = true
+			}
+		}
+
+		return !referenced
+	})
+
+	return referenced
+}
+
+// isIdentifierByte tells you whether the byte can be part of an identifier.
+func isIdentifierByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b >= 0x80
+}
@@ -0,0 +1,11 @@
+package pixy
+
+import "io"
+
+// Writer is the output of stream functions generated for TargetWriter.
+// It's implemented by *strings.Builder, *bytes.Buffer and *bufio.Writer.
+type Writer interface {
+	io.Writer
+	WriteString(s string) (int, error)
+	WriteByte(c byte) error
+}
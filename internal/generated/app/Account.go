@@ -0,0 +1,44 @@
+package app
+
+import (
+	"strings"
+)
+
+import "github.com/aerogo/pixy/internal/generated/ui"
+
+// Account component
+func Account(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(203)
+	_err := streamAccount(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamAccount(_b *strings.Builder, id int) error {
+	_b.WriteString("<section>")
+	if _err := ui.StreamProfile(_b, id); _err != nil {
+		return _err
+	}
+	{
+		_r, _err := renderParallel(0,
+			func() (string, error) { return ui.Profile(id + 1) },
+			func() (string, error) { return Footer(), nil },
+		)
+		if _err != nil {
+			return _err
+		}
+		for _, _s := range _r {
+			_b.WriteString(_s)
+		}
+	}
+	_b.WriteString("</section>")
+	return nil
+}
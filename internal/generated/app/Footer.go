@@ -0,0 +1,23 @@
+package app
+
+import (
+	"strings"
+)
+
+import "github.com/aerogo/pixy/internal/generated/ui"
+
+// Footer component
+func Footer() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(40)
+	streamFooter(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamFooter(_b *strings.Builder) {
+	_b.WriteString("<footer><p>")
+	_b.WriteString(ui.Button("Contact", "link"))
+	_b.WriteString("</p></footer>")
+}
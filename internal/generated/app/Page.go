@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+import "github.com/aerogo/pixy/internal/generated/ui"
+import str "strings"
+
+// Page component
+func Page(title string, count int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(347)
+	streamPage(_b, title, count)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b *strings.Builder, title string, count int) {
+	_b.WriteString("<main>")
+	ui.StreamCard(_b, str.ToUpper(title), fmt.Sprintf("%d items", count))
+	ui.StreamButtonWith(_b, ui.ButtonProps{Label: "Save", Kind: "primary"})
+	{
+		_r, _ := renderParallel(0,
+			func() (string, error) { return ui.Button("Delete", "danger"), nil },
+			func() (string, error) { return ui.ButtonWith(ui.ButtonProps{Label: "Archive", Kind: "warning"}), nil },
+			func() (string, error) { return Footer(), nil },
+		)
+		for _, _s := range _r {
+			_b.WriteString(_s)
+		}
+	}
+	_b.WriteString("<p class='ui'>Text</p></main>")
+}
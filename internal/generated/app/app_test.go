@@ -0,0 +1,32 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/aerogo/pixy/internal/generated/ui"
+	"github.com/akyoto/assert"
+)
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page("Cart", 3), "<main>"+
+		"<section class='card'><h2>CART</h2><p>3 items</p></section>"+
+		"<button class='button primary'>Save</button>"+
+		"<button class='button danger'>Delete</button>"+
+		"<button class='button warning'>Archive</button>"+
+		"<footer><p><button class='button link'>Contact</button></p></footer>"+
+		"<p class='ui'>Text</p>"+
+		"</main>")
+}
+
+func TestAccount(t *testing.T) {
+	html, err := Account(1)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<section>"+
+		"<p class='profile'>User 1</p>"+
+		"<p class='profile'>User 2</p>"+
+		"<footer><p><button class='button link'>Contact</button></p></footer>"+
+		"</section>")
+
+	_, err = Account(0)
+	assert.Equal(t, err, ui.ErrUnknownProfile)
+}
@@ -0,0 +1,25 @@
+package buffer
+
+import (
+	"bytes"
+)
+
+// List component
+func List(items []string) string {
+	_b := acquireBytesBuffer()
+	_b.Grow(34)
+	streamList(_b, items)
+	_s := _b.String()
+	releaseBytesBuffer(_b)
+	return _s
+}
+
+func streamList(_b *bytes.Buffer, items []string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
@@ -0,0 +1,23 @@
+package buffer
+
+import (
+	"bytes"
+)
+
+// Page component
+func Page(title string, items []string) string {
+	_b := acquireBytesBuffer()
+	_b.Grow(119)
+	streamPage(_b, title, items)
+	_s := _b.String()
+	releaseBytesBuffer(_b)
+	return _s
+}
+
+func streamPage(_b *bytes.Buffer, title string, items []string) {
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, title)
+	_b.WriteString("</title></head><body>")
+	streamList(_b, items)
+	_b.WriteString("</body></html>")
+}
@@ -0,0 +1,30 @@
+package buffer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+const expected = "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana &amp; Cherry</li></ul></body></html>"
+
+var items = []string{"Apple", "Banana & Cherry"}
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page("Fruits", items), expected)
+	assert.Equal(t, Page("Fruits", items), expected)
+}
+
+func TestStreamPage(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	streamPage(buffer, "Fruits", items)
+	assert.Equal(t, buffer.String(), expected)
+}
+
+func TestReleaseLargeBuffer(t *testing.T) {
+	large := strings.Repeat("x", maxPooledBufferSize)
+	assert.Equal(t, List([]string{large}), "<ul><li>"+large+"</li></ul>")
+	assert.Equal(t, List(items), "<ul><li>Apple</li><li>Banana &amp; Cherry</li></ul>")
+}
@@ -0,0 +1,27 @@
+package buffered
+
+import (
+	"bufio"
+)
+
+// List component
+func List(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(34)
+	_w := acquireBufioWriter(_b)
+	streamList(_w, items)
+	releaseBufioWriter(_w)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamList(_b *bufio.Writer, items []string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
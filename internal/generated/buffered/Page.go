@@ -0,0 +1,25 @@
+package buffered
+
+import (
+	"bufio"
+)
+
+// Page component
+func Page(title string, items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(119)
+	_w := acquireBufioWriter(_b)
+	streamPage(_w, title, items)
+	releaseBufioWriter(_w)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b *bufio.Writer, title string, items []string) {
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, title)
+	_b.WriteString("</title></head><body>")
+	streamList(_b, items)
+	_b.WriteString("</body></html>")
+}
@@ -0,0 +1,26 @@
+package buffered
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+const expected = "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana &amp; Cherry</li></ul></body></html>"
+
+var items = []string{"Apple", "Banana & Cherry"}
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page("Fruits", items), expected)
+	assert.Equal(t, Page("Fruits", items), expected)
+}
+
+func TestStreamPage(t *testing.T) {
+	builder := &strings.Builder{}
+	writer := bufio.NewWriter(builder)
+	streamPage(writer, "Fruits", items)
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, builder.String(), expected)
+}
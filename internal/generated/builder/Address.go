@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Address component
+func Address(label string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamAddress(_b, label)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamAddress(_b *strings.Builder, label string) {
+	_b.WriteString("<p>")
+	writeEscaped(_b, *(&label))
+	_b.WriteString("</p>")
+}
@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Article component
+func Article(title string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(623)
+	streamArticle(_b, title)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamArticle(_b *strings.Builder, title string) {
+	_b.WriteString("<article><h1>")
+	writeEscaped(_b, title)
+	_b.WriteString("</h1><h2>Pixy <em>filters</em></h2><p>Markdown is converted at <strong>compile time</strong>,\neven across lines with <code>code</code> &amp; &lt;tags&gt;.</p><p>Wrapped prose\nstays in one paragraph.</p><p>An empty line starts the next one.</p><ul><li>One</li><li><a href='https://example.com/?a=1&amp;b=2'>Two</a></li></ul><ol><li>First</li><li>Second</li></ol><blockquote><p>Quoted <em>text</em> in snake_case</p></blockquote><pre><code class='language-go'>println(&#34;&lt;hi&gt;&#34;)</code></pre><hr><style>article {\ncolor: red;\n}</style><script>console.log(\"ready\")</script></article>SHOUT")
+}
@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"strings"
+)
+
+const staticAvatar = "<img src='/avatar.png'>"
+
+// Avatar component
+func Avatar() string {
+	return staticAvatar
+}
+
+func streamAvatar(_b *strings.Builder) {
+	_b.WriteString(staticAvatar)
+}
@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Badge component
+func Badge(label string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(43)
+	streamBadge(_b, label)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamBadge(_b *strings.Builder, label string) {
+	_b.WriteString("<span class='badge'>")
+	writeEscaped(_b, label)
+	_b.WriteString("</span>")
+}
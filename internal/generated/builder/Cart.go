@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Cart component
+func Cart(prices []Price) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamCart(_b, prices)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamCart(_b *strings.Builder, prices []Price) {
+	_b.WriteString("<p>")
+	writeEscaped(_b, "Total: "+total(prices).String())
+	_b.WriteString("</p>")
+}
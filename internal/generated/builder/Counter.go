@@ -0,0 +1,27 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Counter component
+func Counter(count int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(80)
+	streamCounter(_b, count)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamCounter(_b *strings.Builder, count int) {
+	_b.WriteString("<ul class='counter'>")
+	for i := 0; i < count; i++ {
+		_b.WriteString("<li data-index='")
+		writeEscaped(_b, i)
+		_b.WriteString("'>")
+		writeEscaped(_b, i)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
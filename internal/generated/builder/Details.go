@@ -0,0 +1,34 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Details component
+func Details(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	_err := streamDetails(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamDetails(_b *strings.Builder, id int) error {
+	_b.WriteString("<p>")
+	{
+		_v, _err := bio(id)
+		if _err != nil {
+			return _err
+		}
+		_b.WriteString(_v)
+	}
+	_b.WriteString("</p>")
+	return nil
+}
@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Escaped component
+func Escaped(value interface{}) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(48)
+	streamEscaped(_b, value)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamEscaped(_b *strings.Builder, value interface{}) {
+	_b.WriteString("<p title='")
+	writeEscaped(_b, value)
+	_b.WriteString("'>")
+	writeEscaped(_b, value)
+	_b.WriteString("</p>")
+}
@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"strings"
+)
+
+const staticFooter = "<footer><p title='Tom&#39;s page'>Copyright 2019 &amp; later</p><span class='icon' aria-hidden></span><span class='badge'>new</span></footer>"
+
+// Footer component
+func Footer() string {
+	return staticFooter
+}
+
+func streamFooter(_b *strings.Builder) {
+	_b.WriteString(staticFooter)
+}
@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Gallery component
+func Gallery(images []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(194)
+	streamGallery(_b, images)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamGallery(_b *strings.Builder, images []string) {
+	_b.WriteString("<h1>Gallery</h1><img src='https://cdn.example.com/logo.png' alt='Logo' loading='lazy'>")
+	for _, image := range images {
+		_b.WriteString("<figure><img src='")
+		writeEscaped(_b, "https://cdn.example.com"+image)
+		_b.WriteString("' alt='' loading='lazy'><figcaption>")
+		writeEscaped(_b, image)
+		_b.WriteString("</figcaption></figure>")
+	}
+}
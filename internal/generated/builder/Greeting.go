@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Greeting component
+func Greeting(name string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(91)
+	streamGreeting(_b, name)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamGreeting(_b *strings.Builder, name string) {
+	_b.WriteString("<p>name</p>")
+	{
+		count := 2
+		_b.WriteString("<ul class='counter'>")
+		for i := 0; i < count; i++ {
+			_b.WriteString("<li data-index='")
+			writeEscaped(_b, i)
+			_b.WriteString("'>")
+			writeEscaped(_b, i)
+			_b.WriteString("</li>")
+		}
+		_b.WriteString("</ul>")
+	}
+}
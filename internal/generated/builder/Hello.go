@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Hello component
+func Hello(person string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(25)
+	streamHello(_b, person)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamHello(_b *strings.Builder, person string) {
+	_b.WriteString("<h1>")
+	writeEscaped(_b, "Hello "+person)
+	_b.WriteString("</h1>")
+}
@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"strings"
+)
+
+const staticIcon = "<span class='icon' aria-hidden></span>"
+
+// Icon component
+func Icon() string {
+	return staticIcon
+}
+
+func streamIcon(_b *strings.Builder) {
+	_b.WriteString(staticIcon)
+}
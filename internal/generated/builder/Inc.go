@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Inc component
+func Inc(b byte) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamInc(_b, b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamInc(_b *strings.Builder, b byte) {
+	_b.WriteString("<p>")
+	writeEscaped(_b, b+1)
+	_b.WriteString("</p>")
+}
@@ -0,0 +1,34 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Layout component
+func Layout(title string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(326)
+	streamLayout(_b, title)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamLayout(_b *strings.Builder, title string) {
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, title)
+	_b.WriteString("</title></head><body><h1>Hello World</h1>")
+	{
+		count := 3
+		_b.WriteString("<ul class='counter'>")
+		for i := 0; i < count; i++ {
+			_b.WriteString("<li data-index='")
+			writeEscaped(_b, i)
+			_b.WriteString("'>")
+			writeEscaped(_b, i)
+			_b.WriteString("</li>")
+		}
+		_b.WriteString("</ul>")
+	}
+	_b.WriteString("<footer><p title='Tom&#39;s page'>Copyright 2019 &amp; later</p><span class='icon' aria-hidden></span><span class='badge'>new</span></footer></body></html>")
+}
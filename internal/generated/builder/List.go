@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"strings"
+)
+
+var sizeHintList int64 = 34
+
+// List component
+func List(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(sizeHint(&sizeHintList))
+	streamList(_b, items)
+	updateSizeHint(&sizeHintList, _b.Len())
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamList(_b *strings.Builder, items []string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
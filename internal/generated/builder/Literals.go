@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Literals component
+func Literals() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(75)
+	streamLiterals(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamLiterals(_b *strings.Builder) {
+	for _, number := range []int{1, 2} {
+		_b.WriteString("<span>")
+		writeEscaped(_b, number)
+		_b.WriteString("</span>")
+	}
+	for _, row := range [][]string{{"a", "b"}, {"c"}} {
+		_b.WriteString("<p>")
+		writeEscaped(_b, len(row))
+		_b.WriteString("</p>")
+	}
+	_b.WriteString("<p>")
+	writeEscaped(_b, map[string]float64{"half": 0.5}["half"])
+	_b.WriteString("</p>")
+}
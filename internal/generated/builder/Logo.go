@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"strings"
+)
+
+const staticLogo = "<span>Logo</span>"
+
+// Logo component
+func Logo() string {
+	return staticLogo
+}
+
+func streamLogo(_b *strings.Builder) {
+	_b.WriteString(staticLogo)
+}
@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Numbers component
+func Numbers() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(54)
+	streamNumbers(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamNumbers(_b *strings.Builder) {
+	{
+		b := byte(255)
+		_b.WriteString("<p>")
+		writeEscaped(_b, b+1)
+		_b.WriteString("</p>")
+	}
+	_b.WriteString("<p>2</p>")
+	{
+		label := "x"
+		_b.WriteString("<p>")
+		writeEscaped(_b, *(&label))
+		_b.WriteString("</p>")
+	}
+}
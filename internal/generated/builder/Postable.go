@@ -0,0 +1,94 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Postable component
+func Postable(post *Post) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(194)
+	streamPostable(_b, post)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPostable(_b *strings.Builder, post *Post) {
+	_b.WriteString("<article id='post'><h2>")
+	writeEscaped(_b, post.Title)
+	_b.WriteString("</h2><div id='likes-count'><span>")
+	writeEscaped(_b, post.Likes)
+	_b.WriteString("</span></div><ul>")
+	for _, comment := range post.Comments {
+		_b.WriteString("<li id='")
+		writeEscaped(_b, comment)
+		_b.WriteString("'>")
+		writeEscaped(_b, comment)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+	if post.Likes > 1 {
+		_b.WriteString("<p id='popular'>Popular</p>")
+	}
+	_b.WriteString("</article>")
+}
+
+// PostableFragment component
+func PostableFragment(fragment string, post *Post) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(334)
+	_err := streamPostableFragment(_b, fragment, post)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamPostableFragment(_b *strings.Builder, fragment string, post *Post) error {
+	if fragment == "post" {
+		_b.WriteString("<article id='post'><h2>")
+		writeEscaped(_b, post.Title)
+		_b.WriteString("</h2><div id='likes-count'><span>")
+		writeEscaped(_b, post.Likes)
+		_b.WriteString("</span></div><ul>")
+		for _, comment := range post.Comments {
+			_b.WriteString("<li id='")
+			writeEscaped(_b, comment)
+			_b.WriteString("'>")
+			writeEscaped(_b, comment)
+			_b.WriteString("</li>")
+		}
+		_b.WriteString("</ul>")
+		if post.Likes > 1 {
+			_b.WriteString("<p id='popular'>Popular</p>")
+		}
+		_b.WriteString("</article>")
+	} else if fragment == "likes-count" {
+		_b.WriteString("<div id='likes-count'><span>")
+		writeEscaped(_b, post.Likes)
+		_b.WriteString("</span></div>")
+	} else if fragment == "comments" {
+		_b.WriteString("<ul>")
+		for _, comment := range post.Comments {
+			_b.WriteString("<li id='")
+			writeEscaped(_b, comment)
+			_b.WriteString("'>")
+			writeEscaped(_b, comment)
+			_b.WriteString("</li>")
+		}
+		_b.WriteString("</ul>")
+	} else if fragment == "popular" {
+		if post.Likes > 1 {
+			_b.WriteString("<p id='popular'>Popular</p>")
+		}
+	} else {
+		return errUnknownFragment(fragment)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Product component
+func Product(title string, price Price) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(103)
+	streamProduct(_b, title, price)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamProduct(_b *strings.Builder, title string, price Price) {
+	_b.WriteString("<article class='product'><h2>")
+	writeEscaped(_b, shorten(title))
+	_b.WriteString("</h2><span class='price'>")
+	writeEscaped(_b, price.String())
+	_b.WriteString("</span></article>")
+}
+
+// maxTitle is the maximum length of a title.
+const maxTitle = 10
+
+// receipt is the note below the cart.
+const receipt = `Thank you!
+
+Come again.`
+
+// Price is an amount in cents.
+type Price int
+
+// String formats the price in dollars.
+func (price Price) String() string {
+	return fmt.Sprintf("$%d.%02d", price/100, price%100)
+}
+
+// shorten cuts off long titles.
+func shorten(title string) string {
+	if len(title) <= maxTitle {
+		return title
+	}
+
+	return strings.TrimSpace(title[:maxTitle]) + "…"
+}
+
+func total(prices []Price) (sum Price) {
+	for _, price := range prices {
+		sum += price
+	}
+
+	return sum
+}
@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Profile component
+func Profile(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(90)
+	_err := streamProfile(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamProfile(_b *strings.Builder, id int) error {
+	_b.WriteString("<article><h1>")
+	{
+		_v, _err := name(id)
+		if _err != nil {
+			return _err
+		}
+		writeEscaped(_b, _v)
+	}
+	_b.WriteString("</h1>")
+	if _err := streamDetails(_b, id); _err != nil {
+		return _err
+	}
+	_b.WriteString("<img src='/avatar.png'></article>")
+	return nil
+}
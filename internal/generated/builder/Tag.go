@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Tag component
+func Tag(name string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(32)
+	streamTag(_b, name)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamTag(_b *strings.Builder, name string) {
+	_b.WriteString("<i class='")
+	writeEscaped(_b, name)
+	_b.WriteString("'></i>")
+}
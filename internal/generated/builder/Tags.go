@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"strings"
+)
+
+const staticTags = "<i class='a&amp;b&lt;c&gt;'></i>"
+
+// Tags component
+func Tags() string {
+	return staticTags
+}
+
+func streamTags(_b *strings.Builder) {
+	_b.WriteString(staticTags)
+}
@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestSizeHint(t *testing.T) {
+	items := make([]string, 100)
+
+	for index := range items {
+		items[index] = strings.Repeat("x", 10)
+	}
+
+	atomic.StoreInt64(&sizeHintList, 0)
+	output := List(items)
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, List(items), output)
+	}
+
+	hint := atomic.LoadInt64(&sizeHintList)
+	assert.True(t, hint > int64(len(output))*9/10)
+	assert.True(t, hint <= int64(len(output)))
+}
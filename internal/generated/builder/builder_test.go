@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestHello(t *testing.T) {
+	assert.Equal(t, Hello("<World>"), "<h1>Hello &lt;World&gt;</h1>")
+}
+
+func TestLayout(t *testing.T) {
+	assert.Equal(t, Layout("Home & Garden"), "<!DOCTYPE html><html><head><title>Home &amp; Garden</title></head><body><h1>Hello World</h1><ul class='counter'><li data-index='0'>0</li><li data-index='1'>1</li><li data-index='2'>2</li></ul><footer><p title='Tom&#39;s page'>Copyright 2019 &amp; later</p><span class='icon' aria-hidden></span><span class='badge'>new</span></footer></body></html>")
+}
+
+func TestFooter(t *testing.T) {
+	assert.Equal(t, Footer(), staticFooter)
+	assert.Equal(t, Footer(), "<footer><p title='Tom&#39;s page'>Copyright 2019 &amp; later</p><span class='icon' aria-hidden></span><span class='badge'>new</span></footer>")
+}
+
+func TestEscaped(t *testing.T) {
+	values := []interface{}{
+		"",
+		"plain",
+		`<a href="x">'Tom' & "Jerry"</a>`,
+		-42,
+		int8(-8),
+		int16(16),
+		int32(32),
+		int64(-1 << 62),
+		uint(42),
+		uint8(8),
+		uint16(16),
+		uint32(32),
+		uint64(1 << 63),
+		float32(1.1),
+		0.1,
+		1e21,
+		123456789.0,
+		-0.000001,
+		true,
+		false,
+		nil,
+		[]string{"<b>"},
+	}
+
+	for _, value := range values {
+		escaped := html.EscapeString(fmt.Sprint(value))
+		assert.Equal(t, Escaped(value), "<p title='"+escaped+"'>"+escaped+"</p>")
+	}
+}
+
+func TestWriteEscapedAllocations(t *testing.T) {
+	b := &strings.Builder{}
+	b.Grow(4096)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		writeEscaped(b, "Tom & Jerry")
+		writeEscaped(b, 42)
+		writeEscaped(b, 3.14)
+		writeEscaped(b, true)
+	})
+
+	assert.Equal(t, allocs, 0.0)
+}
+
+func TestBadge(t *testing.T) {
+	assert.Equal(t, Badge("<new>"), "<span class='badge'>&lt;new&gt;</span>")
+}
+
+func TestTags(t *testing.T) {
+	assert.Equal(t, Tags(), Tag("a&b<c>"))
+	assert.Equal(t, Tags(), "<i class='a&amp;b&lt;c&gt;'></i>")
+}
+
+func TestNumbers(t *testing.T) {
+	assert.Equal(t, Numbers(), Inc(255)+Inc(1)+Address("x"))
+	assert.Equal(t, Numbers(), "<p>0</p><p>2</p><p>x</p>")
+}
+
+func TestConcurrentRendering(t *testing.T) {
+	const goroutines = 16
+	const iterations = 500
+	done := make(chan struct{})
+
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer func() { done <- struct{}{} }()
+
+			for i := 0; i < iterations; i++ {
+				person := fmt.Sprint(g, "-", i)
+				expected := "<h1>Hello " + person + "</h1>"
+
+				if output := Hello(person); output != expected {
+					t.Errorf("Hello(%q) returned %q", person, output)
+					return
+				}
+			}
+		}(g)
+	}
+
+	for g := 0; g < goroutines; g++ {
+		<-done
+	}
+}
+
+func TestGreetings(t *testing.T) {
+	assert.Equal(t, Greetings(), "<p>name</p>"+Counter(2))
+}
+
+func TestLiterals(t *testing.T) {
+	assert.Equal(t, Literals(), "<span>1</span><span>2</span><p>2</p><p>1</p><p>0.5</p>")
+}
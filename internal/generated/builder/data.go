@@ -0,0 +1,37 @@
+package builder
+
+import "errors"
+
+var errNotFound = errors.New("not found")
+
+var users = map[int][2]string{
+	1: {"Alice <3", "<em>Likes Go.</em>"},
+	2: {"Bob", ""},
+}
+
+func name(id int) (string, error) {
+	user, exists := users[id]
+
+	if !exists {
+		return "", errNotFound
+	}
+
+	return user[0], nil
+}
+
+func bio(id int) (string, error) {
+	user := users[id]
+
+	if user[1] == "" {
+		return "", errNotFound
+	}
+
+	return user[1], nil
+}
+
+// Post is a post with likes and comments.
+type Post struct {
+	Title    string
+	Likes    int
+	Comments []string
+}
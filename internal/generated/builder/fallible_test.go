@@ -0,0 +1,25 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestProfile(t *testing.T) {
+	html, err := Profile(1)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<article><h1>Alice &lt;3</h1><p><em>Likes Go.</em></p><img src='/avatar.png'></article>")
+}
+
+func TestExpressionError(t *testing.T) {
+	html, err := Profile(3)
+	assert.Equal(t, err, errNotFound)
+	assert.Equal(t, html, "")
+}
+
+func TestNestedComponentError(t *testing.T) {
+	html, err := Profile(2)
+	assert.Equal(t, err, errNotFound)
+	assert.Equal(t, html, "")
+}
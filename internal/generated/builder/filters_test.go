@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestFilters(t *testing.T) {
+	html := Article("<Filters>")
+	assert.Contains(t, html, "<h1>&lt;Filters&gt;</h1><h2>Pixy <em>filters</em></h2>")
+	assert.Contains(t, html, "<p>Wrapped prose\nstays in one paragraph.</p><p>An empty line starts the next one.</p>")
+	assert.Contains(t, html, "<ul><li>One</li><li><a href='https://example.com/?a=1&amp;b=2'>Two</a></li></ul>")
+	assert.Contains(t, html, "<style>article {\ncolor: red;\n}</style><script>console.log(\"ready\")</script>")
+	assert.Contains(t, html, "</article>SHOUT")
+}
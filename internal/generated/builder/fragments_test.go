@@ -0,0 +1,40 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+var post = &Post{
+	Title:    "Hello",
+	Likes:    2,
+	Comments: []string{"First", "Second"},
+}
+
+func TestPostable(t *testing.T) {
+	assert.Equal(t, Postable(post), "<article id='post'><h2>Hello</h2><div id='likes-count'><span>2</span></div><ul><li id='First'>First</li><li id='Second'>Second</li></ul><p id='popular'>Popular</p></article>")
+}
+
+func TestPostableFragment(t *testing.T) {
+	for name, expected := range map[string]string{
+		"post":        Postable(post),
+		"likes-count": "<div id='likes-count'><span>2</span></div>",
+		"comments":    "<ul><li id='First'>First</li><li id='Second'>Second</li></ul>",
+		"popular":     "<p id='popular'>Popular</p>",
+	} {
+		html, err := PostableFragment(name, post)
+		assert.Nil(t, err)
+		assert.Equal(t, html, expected)
+	}
+
+	html, err := PostableFragment("popular", &Post{Likes: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, html, "")
+
+	for _, name := range []string{"First", "unknown"} {
+		_, err = PostableFragment(name, post)
+		assert.NotNil(t, err)
+		assert.Equal(t, err.Error(), "unknown fragment \""+name+"\"")
+	}
+}
@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestHelpers(t *testing.T) {
+	assert.Equal(t, shorten("Keyboard"), "Keyboard")
+	assert.Equal(t, shorten("Mechanical keyboard"), "Mechanical…")
+	assert.Equal(t, Price(1999).String(), "$19.99")
+	assert.Equal(t, receipt, "Thank you!\n\nCome again.")
+}
+
+func TestProduct(t *testing.T) {
+	html := Product("Mechanical keyboard", 1999)
+	assert.Equal(t, html, "<article class='product'><h2>Mechanical…</h2><span class='price'>$19.99</span></article>")
+}
+
+func TestCart(t *testing.T) {
+	assert.Equal(t, Cart([]Price{1999, 501}), "<p>Total: $25.00</p>")
+}
@@ -0,0 +1,13 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestTransforms(t *testing.T) {
+	html := Gallery([]string{"/cat.jpg"})
+	assert.Equal(t, html, "<h1>Gallery</h1><img src='https://cdn.example.com/logo.png' alt='Logo' loading='lazy'><figure><img src='https://cdn.example.com/cat.jpg' alt='' loading='lazy'><figcaption>/cat.jpg</figcaption></figure>")
+	assert.NotContains(t, html, "data-test")
+}
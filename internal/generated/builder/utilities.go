@@ -0,0 +1,306 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var _pool = sync.Pool{
+	New: func() interface{} {
+		return &strings.Builder{}
+	},
+}
+
+func acquireStringsBuilder() *strings.Builder {
+	return _pool.Get().(*strings.Builder)
+}
+
+// releaseStringsBuilder returns the builder to the pool.
+// The string returned by builder.String() shares the buffer of the builder,
+// therefore it must be called after the string has been retrieved.
+// Reset hands the buffer over to that string, so pooled builders are always
+// empty and can't keep large buffers alive no matter how much they grew.
+func releaseStringsBuilder(builder *strings.Builder) {
+	builder.Reset()
+	_pool.Put(builder)
+}
+
+// renderParallel calls the render functions concurrently and returns their outputs in order.
+// At most limit functions run at the same time unless limit is zero.
+// The first error in order is returned and a panic is re-raised in the calling goroutine.
+func renderParallel(limit int, render ...func() (string, error)) ([]string, error) {
+	outputs := make([]string, len(render))
+	errs := make([]error, len(render))
+	panics := make([]interface{}, len(render))
+	wg := sync.WaitGroup{}
+	wg.Add(len(render))
+
+	var semaphore chan struct{}
+
+	if limit > 0 {
+		semaphore = make(chan struct{}, limit)
+	}
+
+	for index, function := range render {
+		if semaphore != nil {
+			semaphore <- struct{}{}
+		}
+
+		go func(index int, function func() (string, error)) {
+			defer wg.Done()
+
+			defer func() {
+				panics[index] = recover()
+
+				if semaphore != nil {
+					<-semaphore
+				}
+			}()
+
+			outputs[index], errs[index] = function()
+		}(index, function)
+	}
+
+	wg.Wait()
+
+	for _, value := range panics {
+		if value != nil {
+			panic(value)
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return outputs, nil
+}
+
+// deferredFragment is the content of a deferred block that is rendered in the background.
+type deferredFragment struct {
+	id     string
+	done   chan struct{}
+	output string
+	err    error
+	panic  interface{}
+}
+
+// deferredRender contains the deferred blocks queued while a component writes to a writer.
+type deferredRender struct {
+	fragments []*deferredFragment
+}
+
+var (
+	_deferredID    int64
+	_deferredMutex sync.Mutex
+	_deferred      = map[interface{}]*deferredRender{}
+)
+
+// beginDeferred starts queueing the deferred blocks written to the writer.
+// It returns nil if a component further up is already doing so,
+// in which case that component owns the queued blocks and writes them.
+func beginDeferred(writer interface{}) *deferredRender {
+	_deferredMutex.Lock()
+	defer _deferredMutex.Unlock()
+
+	if _deferred[writer] != nil {
+		return nil
+	}
+
+	render := &deferredRender{}
+	_deferred[writer] = render
+	return render
+}
+
+// endDeferred stops queueing the deferred blocks of the render that was started with beginDeferred.
+// Blocks that haven't been written are discarded so that they never end up in the output
+// of another render that gets the same writer from a pool.
+func endDeferred(writer interface{}, render *deferredRender) {
+	if render == nil {
+		return
+	}
+
+	_deferredMutex.Lock()
+	delete(_deferred, writer)
+	_deferredMutex.Unlock()
+}
+
+// deferContent starts rendering the content of a deferred block in the background
+// and queues it until flushDeferred is called for the same writer.
+// It returns the id of the element containing the placeholder.
+func deferContent(writer interface{}, render func(*strings.Builder) error) string {
+	fragment := &deferredFragment{
+		id:   strconv.FormatInt(atomic.AddInt64(&_deferredID, 1), 10),
+		done: make(chan struct{}),
+	}
+
+	_deferredMutex.Lock()
+	queue := _deferred[writer]
+	queue.fragments = append(queue.fragments, fragment)
+	_deferredMutex.Unlock()
+
+	go func() {
+		defer close(fragment.done)
+
+		defer func() {
+			fragment.panic = recover()
+		}()
+
+		buffer := acquireStringsBuilder()
+		fragment.err = render(buffer)
+		fragment.output = buffer.String()
+		releaseStringsBuilder(buffer)
+	}()
+
+	return fragment.id
+}
+
+// flushDeferred waits for the deferred blocks queued for the writer and writes their content
+// in template elements, each followed by a script that replaces the placeholder.
+// It returns the first error of the deferred blocks and re-raises their panics.
+func flushDeferred(_b *strings.Builder) error {
+	_deferredMutex.Lock()
+	var fragments []*deferredFragment
+
+	if render := _deferred[_b]; render != nil {
+		fragments = render.fragments
+		render.fragments = nil
+	}
+
+	_deferredMutex.Unlock()
+
+	if len(fragments) == 0 {
+		return nil
+	}
+
+	var err error
+
+	for _, fragment := range fragments {
+		<-fragment.done
+
+		if fragment.panic != nil {
+			panic(fragment.panic)
+		}
+
+		if fragment.err != nil {
+			if err == nil {
+				err = fragment.err
+			}
+
+			continue
+		}
+
+		_b.WriteString("<template id='pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("-content'>")
+		_b.WriteString(fragment.output)
+		_b.WriteString("</template><script>(function(){var p=document.getElementById('pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("'),t=document.getElementById('pixy-deferred-")
+		_b.WriteString(fragment.id)
+		_b.WriteString("-content');p.replaceWith(t.content);t.remove()})()</script>")
+	}
+
+	return err
+}
+
+// finishDeferred writes the deferred blocks that are still queued at the end of the render
+// if the render was started by the calling component, e.g. because it has no body element.
+func finishDeferred(_b *strings.Builder, render *deferredRender) error {
+	if render == nil {
+		return nil
+	}
+
+	return flushDeferred(_b)
+}
+
+// sizeHint returns the current size hint for the output of a component.
+func sizeHint(hint *int64) int {
+	return int(atomic.LoadInt64(hint))
+}
+
+// updateSizeHint moves the size hint of a component towards the actual output size
+// using an exponentially weighted moving average.
+func updateSizeHint(hint *int64, size int) {
+	previous := atomic.LoadInt64(hint)
+	atomic.StoreInt64(hint, previous+(int64(size)-previous)/8)
+}
+
+// errUnknownFragment returns the error for a fragment name that a component doesn't have.
+func errUnknownFragment(name string) error {
+	return fmt.Errorf("unknown fragment %q", name)
+}
+
+// writeEscaped writes the HTML-escaped representation of value to the builder.
+// Strings, numbers and booleans are written without calling fmt.Sprint.
+func writeEscaped(_b *strings.Builder, value interface{}) {
+	var buffer [64]byte
+
+	switch value := value.(type) {
+	case string:
+		writeEscapedString(_b, value)
+	case int:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int8:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int16:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int32:
+		_b.Write(strconv.AppendInt(buffer[:0], int64(value), 10))
+	case int64:
+		_b.Write(strconv.AppendInt(buffer[:0], value, 10))
+	case uint:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint8:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint16:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint32:
+		_b.Write(strconv.AppendUint(buffer[:0], uint64(value), 10))
+	case uint64:
+		_b.Write(strconv.AppendUint(buffer[:0], value, 10))
+	case float32:
+		_b.Write(strconv.AppendFloat(buffer[:0], float64(value), 'g', -1, 32))
+	case float64:
+		_b.Write(strconv.AppendFloat(buffer[:0], value, 'g', -1, 64))
+	case bool:
+		_b.Write(strconv.AppendBool(buffer[:0], value))
+	default:
+		writeEscapedString(_b, fmt.Sprint(value))
+	}
+}
+
+// writeEscapedString writes s to the builder, escaping the same characters as html.EscapeString.
+func writeEscapedString(_b *strings.Builder, s string) {
+	last := 0
+
+	for i := 0; i < len(s); i++ {
+		var escaped string
+
+		switch s[i] {
+		case '&':
+			escaped = "&amp;"
+		case '\'':
+			escaped = "&#39;"
+		case '<':
+			escaped = "&lt;"
+		case '>':
+			escaped = "&gt;"
+		case '"':
+			escaped = "&#34;"
+		default:
+			continue
+		}
+
+		_b.WriteString(s[last:i])
+		_b.WriteString(escaped)
+		last = i + 1
+	}
+
+	_b.WriteString(s[last:])
+}
@@ -0,0 +1,27 @@
+package contextual
+
+import (
+	"context"
+	"io"
+)
+
+const staticFooter = "<footer>Done</footer>"
+
+// Footer component
+func Footer(ctx context.Context) (string, error) {
+	if _err := ctx.Err(); _err != nil {
+		return "", _err
+	}
+
+	return staticFooter, nil
+}
+
+func streamFooter(ctx context.Context, _w io.Writer) error {
+	_b := toWriter(_w)
+	if _err := ctx.Err(); _err != nil {
+		return _err
+	}
+
+	_b.WriteString(staticFooter)
+	return nil
+}
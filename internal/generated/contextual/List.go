@@ -0,0 +1,41 @@
+package contextual
+
+import (
+	"context"
+	"io"
+)
+
+// List component
+func List(ctx context.Context, items []string) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(34)
+	_err := streamList(ctx, _b, items)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamList(ctx context.Context, _w io.Writer, items []string) error {
+	_b := toWriter(_w)
+	if _err := ctx.Err(); _err != nil {
+		return _err
+	}
+
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		if _err := ctx.Err(); _err != nil {
+			return _err
+		}
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+	return nil
+}
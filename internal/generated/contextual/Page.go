@@ -0,0 +1,38 @@
+package contextual
+
+import (
+	"context"
+	"io"
+)
+
+// Page component
+func Page(ctx context.Context, items []string) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(140)
+	_err := streamPage(ctx, _b, items)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamPage(ctx context.Context, _w io.Writer, items []string) error {
+	_b := toWriter(_w)
+	if _err := ctx.Err(); _err != nil {
+		return _err
+	}
+
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, ctx.Value(titleKey))
+	_b.WriteString("</title></head><body>")
+	if _err := streamList(ctx, _b, items); _err != nil {
+		return _err
+	}
+	_b.WriteString("<footer>Done</footer></body></html>")
+	return nil
+}
@@ -0,0 +1,76 @@
+package contextual
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+// cancelWriter cancels the context after a number of writes.
+type cancelWriter struct {
+	*httptest.ResponseRecorder
+	cancel func()
+	writes int
+}
+
+func (writer *cancelWriter) WriteString(s string) (int, error) {
+	writer.writes--
+
+	if writer.writes == 0 {
+		writer.cancel()
+	}
+
+	return writer.ResponseRecorder.WriteString(s)
+}
+
+func TestPage(t *testing.T) {
+	ctx := context.WithValue(context.Background(), titleKey, "Fruits")
+	html, err := Page(ctx, []string{"Apple", "Banana"})
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana</li></ul><footer>Done</footer></body></html>")
+}
+
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	html, err := Page(ctx, []string{"Apple"})
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, html, "")
+
+	html, err = Footer(ctx)
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, html, "")
+
+	html, err = Footer(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<footer>Done</footer>")
+}
+
+func TestCancelWhileRendering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), titleKey, "Fruits"))
+	defer cancel()
+
+	writer := &cancelWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		cancel:           cancel,
+		writes:           5,
+	}
+
+	err := streamPage(ctx, writer, []string{"Apple", "Banana", "Cherry"})
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, writer.Body.String(), "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li>")
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render(context.Background(), "List", []string{"Apple"})
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<ul><li>Apple</li></ul>")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Render(ctx, "List", []string{"Apple"})
+	assert.Equal(t, err, context.Canceled)
+}
@@ -0,0 +1,6 @@
+package contextual
+
+type key string
+
+// titleKey is the context key of the page title.
+const titleKey key = "title"
@@ -0,0 +1,165 @@
+package contextual
+
+import (
+	"context"
+	"fmt"
+	"github.com/aerogo/pixy"
+	"reflect"
+)
+
+// ComponentInfo describes a component and its parameters.
+type ComponentInfo struct {
+	Name         string
+	Parameters   []ParameterInfo
+	Variadic     bool
+	ReturnsError bool
+	function     reflect.Value
+	stream       reflect.Value
+}
+
+// ParameterInfo describes a parameter of a component.
+// Default is the zero Value for parameters without a default value.
+type ParameterInfo struct {
+	Name    string
+	Type    reflect.Type
+	Default reflect.Value
+}
+
+// Registry maps the names of the components to their descriptions.
+var Registry = map[string]*ComponentInfo{}
+
+// privateRegistry contains the unexported components,
+// which can only be rendered by dynamic calls in the package.
+var privateRegistry = map[string]*ComponentInfo{}
+
+func init() {
+	Registry["Footer"] = &ComponentInfo{
+		Name:         "Footer",
+		Parameters:   []ParameterInfo{},
+		ReturnsError: true,
+		function:     reflect.ValueOf(Footer),
+		stream:       reflect.ValueOf(streamFooter),
+	}
+
+	Registry["List"] = &ComponentInfo{
+		Name: "List",
+		Parameters: []ParameterInfo{
+			{Name: "items", Type: reflect.TypeOf((*[]string)(nil)).Elem()},
+		},
+		ReturnsError: true,
+		function:     reflect.ValueOf(List),
+		stream:       reflect.ValueOf(streamList),
+	}
+
+	Registry["Page"] = &ComponentInfo{
+		Name: "Page",
+		Parameters: []ParameterInfo{
+			{Name: "items", Type: reflect.TypeOf((*[]string)(nil)).Elem()},
+		},
+		ReturnsError: true,
+		function:     reflect.ValueOf(Page),
+		stream:       reflect.ValueOf(streamPage),
+	}
+}
+
+// Render renders the component with the given name.
+// The arguments are checked against the parameters of the component
+// and variadic parameters receive their arguments as a slice.
+func Render(ctx context.Context, name string, args ...interface{}) (string, error) {
+	component, values, err := registryArguments(name, args, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	call := component.function.Call
+
+	if component.Variadic {
+		call = component.function.CallSlice
+	}
+
+	results := call(append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, values...))
+
+	if len(results) == 2 && !results[1].IsNil() {
+		return "", results[1].Interface().(error)
+	}
+
+	return results[0].String(), nil
+}
+
+// streamDynamic writes the component with the given name to the output.
+func streamDynamic(ctx context.Context, _b pixy.Writer, name string, args ...interface{}) error {
+	component, values, err := registryArguments(name, args, true)
+
+	if err != nil {
+		return err
+	}
+
+	call := component.stream.Call
+
+	if component.Variadic {
+		call = component.stream.CallSlice
+	}
+
+	results := call(append([]reflect.Value{reflect.ValueOf(&ctx).Elem(), reflect.ValueOf(_b)}, values...))
+
+	if len(results) == 1 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
+
+	return nil
+}
+
+// registryArguments returns the component with the given name
+// and the arguments as values of the parameter types.
+// Unexported components are only found if private is true.
+func registryArguments(name string, args []interface{}, private bool) (*ComponentInfo, []reflect.Value, error) {
+	component, exists := Registry[name]
+
+	if !exists && private {
+		component, exists = privateRegistry[name]
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("unknown component %s", name)
+	}
+
+	if len(args) > len(component.Parameters) {
+		return nil, nil, fmt.Errorf("component %s needs %d arguments but got %d", name, len(component.Parameters), len(args))
+	}
+
+	values := make([]reflect.Value, 0, len(component.Parameters))
+
+	for index, arg := range args {
+		parameter := component.Parameters[index]
+
+		if arg == nil {
+			switch parameter.Type.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+				values = append(values, reflect.Zero(parameter.Type))
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("component %s: cannot use nil as %s in parameter %s", name, parameter.Type, parameter.Name)
+		}
+
+		value := reflect.ValueOf(arg)
+
+		if !value.Type().AssignableTo(parameter.Type) {
+			return nil, nil, fmt.Errorf("component %s: cannot use %s as %s in parameter %s", name, value.Type(), parameter.Type, parameter.Name)
+		}
+
+		values = append(values, value)
+	}
+
+	// Omitted trailing arguments use the default values of the parameters
+	for len(values) < len(component.Parameters) && component.Parameters[len(values)].Default.IsValid() {
+		values = append(values, component.Parameters[len(values)].Default)
+	}
+
+	if len(values) != len(component.Parameters) {
+		return nil, nil, fmt.Errorf("component %s needs %d arguments but got %d", name, len(component.Parameters), len(args))
+	}
+
+	return component, values, nil
+}
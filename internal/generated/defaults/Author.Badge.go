@@ -0,0 +1,36 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Badge component
+func (author *Author) Badge(size string) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(54)
+	_err := author.streamBadge(_b, size)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func (author *Author) streamBadge(_b *strings.Builder, size string) error {
+	_b.WriteString("<span class='")
+	writeEscaped(_b, size)
+	_b.WriteString("'>")
+	{
+		_v, _err := author.Initials()
+		if _err != nil {
+			return _err
+		}
+		writeEscaped(_b, _v)
+	}
+	_b.WriteString("</span>")
+	return nil
+}
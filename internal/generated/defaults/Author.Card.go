@@ -0,0 +1,21 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Card component
+func (author *Author) Card() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(57)
+	author.streamCard(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func (author *Author) streamCard(_b *strings.Builder) {
+	_b.WriteString("<section class='Card'><h3>")
+	writeEscaped(_b, author.Name)
+	_b.WriteString("</h3></section>")
+}
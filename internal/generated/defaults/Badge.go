@@ -0,0 +1,49 @@
+package defaults
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Badge component
+func Badge(count int, unit string, max int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(58)
+	streamBadge(_b, count, unit, max)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamBadge(_b *strings.Builder, count int, unit string, max int) {
+	if count > max {
+		_b.WriteString("<span>")
+		writeEscaped(_b, strconv.Itoa(max)+"+ "+unit)
+		_b.WriteString("</span>")
+	} else {
+		_b.WriteString("<span>")
+		writeEscaped(_b, strconv.Itoa(count)+" "+unit)
+		_b.WriteString("</span>")
+	}
+}
+
+// BadgeProps contains the parameters of the Badge component.
+type BadgeProps struct {
+	Count int
+	Unit  string
+	Max   int
+}
+
+// BadgeWith renders the Badge component with the given props.
+func BadgeWith(props BadgeProps) string {
+	return Badge(props.Count, props.Unit, props.Max)
+}
+
+func streamBadgeWith(_b *strings.Builder, props BadgeProps) {
+	streamBadge(_b, props.Count, props.Unit, props.Max)
+}
+
+// NewBadgeProps returns the props of the Badge component with the default values.
+func NewBadgeProps() BadgeProps {
+	return BadgeProps{Unit: "new", Max: 99}
+}
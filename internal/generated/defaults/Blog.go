@@ -0,0 +1,48 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Blog component
+func Blog(posts []*Post, featured *Post) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(137)
+	_err := streamBlog(_b, posts, featured)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamBlog(_b *strings.Builder, posts []*Post, featured *Post) error {
+	if _err := featured.streamCard(_b); _err != nil {
+		return _err
+	}
+	_b.WriteString("<ul>")
+	for _, post := range posts {
+		post.streamSummary(_b)
+	}
+	_b.WriteString("</ul>")
+	return nil
+}
+
+// BlogProps contains the parameters of the Blog component.
+type BlogProps struct {
+	Posts    []*Post
+	Featured *Post
+}
+
+// BlogWith renders the Blog component with the given props.
+func BlogWith(props BlogProps) (string, error) {
+	return Blog(props.Posts, props.Featured)
+}
+
+func streamBlogWith(_b *strings.Builder, props BlogProps) error {
+	return streamBlog(_b, props.Posts, props.Featured)
+}
@@ -0,0 +1,39 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// List component
+func List(items ...string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(34)
+	streamList(_b, items...)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamList(_b *strings.Builder, items ...string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
+
+// ListProps contains the parameters of the List component.
+type ListProps struct {
+	Items []string
+}
+
+// ListWith renders the List component with the given props.
+func ListWith(props ListProps) string {
+	return List(props.Items...)
+}
+
+func streamListWith(_b *strings.Builder, props ListProps) {
+	streamList(_b, props.Items...)
+}
@@ -0,0 +1,30 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Page component
+func Page() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(451)
+	streamPage(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b *strings.Builder) {
+	_b.WriteString("<main>")
+	streamButton(_b, "Save", "primary")
+	streamButton(_b, "Delete", "danger")
+	streamButtonWith(_b, ButtonProps{Label: "Cancel", Kind: "primary"})
+	streamBadge(_b, 7, "new", 99)
+	streamBadge(_b, 120, "unread", 99)
+	streamList(_b, "Tom", "Jerry")
+	streamList(_b)
+	streamList(_b, names...)
+	streamToggleWith(_b, ToggleProps{Label: "Menu", Open: false})
+	streamToggleWith(_b, ToggleProps{Label: "Help", Open: true})
+	_b.WriteString("</main>")
+}
@@ -0,0 +1,59 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Card component
+func (post *Post) Card() (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(132)
+	_err := post.streamCard(_b)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func (post *Post) streamCard(_b *strings.Builder) error {
+	_b.WriteString("<article class='Card'><h2 id='title'>")
+	writeEscaped(_b, post.Title)
+	_b.WriteString("</h2>")
+	if _err := post.Author.streamBadge(_b, "small"); _err != nil {
+		return _err
+	}
+	_b.WriteString("</article>")
+	return nil
+}
+
+// CardFragment component
+func (post *Post) CardFragment(fragment string) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(36)
+	_err := post.streamCardFragment(_b, fragment)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func (post *Post) streamCardFragment(_b *strings.Builder, fragment string) error {
+	if fragment == "title" {
+		_b.WriteString("<h2 id='title'>")
+		writeEscaped(_b, post.Title)
+		_b.WriteString("</h2>")
+	} else {
+		return errUnknownFragment(fragment)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Summary component
+func (post *Post) Summary() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(25)
+	post.streamSummary(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func (post *Post) streamSummary(_b *strings.Builder) {
+	_b.WriteString("<li>")
+	writeEscaped(_b, post.Title)
+	_b.WriteString("</li>")
+}
@@ -0,0 +1,33 @@
+package defaults
+
+import (
+	"strings"
+)
+
+// Profile component
+func Profile(author *Author) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(64)
+	streamProfile(_b, author)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamProfile(_b *strings.Builder, author *Author) {
+	author.streamCard(_b)
+}
+
+// ProfileProps contains the parameters of the Profile component.
+type ProfileProps struct {
+	Author *Author
+}
+
+// ProfileWith renders the Profile component with the given props.
+func ProfileWith(props ProfileProps) string {
+	return Profile(props.Author)
+}
+
+func streamProfileWith(_b *strings.Builder, props ProfileProps) {
+	streamProfile(_b, props.Author)
+}
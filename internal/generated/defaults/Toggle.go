@@ -0,0 +1,42 @@
+package defaults
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Toggle component
+func Toggle(label string, open bool) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamToggle(_b, label, open)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamToggle(_b *strings.Builder, label string, open bool) {
+	_b.WriteString("<p>")
+	writeEscaped(_b, label+" "+strconv.FormatBool(open))
+	_b.WriteString("</p>")
+}
+
+// ToggleProps contains the parameters of the Toggle component.
+type ToggleProps struct {
+	Label string
+	Open  bool
+}
+
+// ToggleWith renders the Toggle component with the given props.
+func ToggleWith(props ToggleProps) string {
+	return Toggle(props.Label, props.Open)
+}
+
+func streamToggleWith(_b *strings.Builder, props ToggleProps) {
+	streamToggle(_b, props.Label, props.Open)
+}
+
+// NewToggleProps returns the props of the Toggle component with the default values.
+func NewToggleProps() ToggleProps {
+	return ToggleProps{Open: true}
+}
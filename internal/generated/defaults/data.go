@@ -0,0 +1,35 @@
+package defaults
+
+import (
+	"errors"
+	"strings"
+)
+
+// names is passed to a variadic component as a slice.
+var names = []string{"Spike", "Tyke"}
+
+// Post is a blog post.
+type Post struct {
+	Title  string
+	Author *Author
+}
+
+// Author is the author of a post.
+type Author struct {
+	Name string
+}
+
+// Initials returns the initials of the author.
+func (author *Author) Initials() (string, error) {
+	if author.Name == "" {
+		return "", errors.New("author has no name")
+	}
+
+	initials := ""
+
+	for _, name := range strings.Fields(author.Name) {
+		initials += name[:1]
+	}
+
+	return initials, nil
+}
@@ -0,0 +1,47 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page(), "<main>"+
+		"<button class='button primary'>Save</button>"+
+		"<button class='button danger'>Delete</button>"+
+		"<button class='button primary'>Cancel</button>"+
+		"<span>7 new</span>"+
+		"<span>99+ unread</span>"+
+		"<ul><li>Tom</li><li>Jerry</li></ul>"+
+		"<ul></ul>"+
+		"<ul><li>Spike</li><li>Tyke</li></ul>"+
+		"<p>Menu false</p>"+
+		"<p>Help true</p>"+
+		"</main>")
+}
+
+func TestWith(t *testing.T) {
+	props := NewButtonProps()
+	props.Label = "Save"
+	assert.Equal(t, ButtonWith(props), Button("Save", "primary"))
+	assert.Equal(t, ButtonWith(ButtonProps{Label: "Save"}), Button("Save", ""))
+
+	badge := NewBadgeProps()
+	badge.Count = 120
+	assert.Equal(t, BadgeWith(badge), Badge(120, "new", 99))
+	assert.Equal(t, BadgeWith(BadgeProps{Count: 3, Unit: "unread", Max: 0}), Badge(3, "unread", 0))
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render("Badge", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, html, Badge(3, "new", 99))
+
+	html, err = Render("List", []string{"Tom"})
+	assert.Nil(t, err)
+	assert.Equal(t, html, List("Tom"))
+
+	_, err = Render("Button")
+	assert.NotNil(t, err)
+}
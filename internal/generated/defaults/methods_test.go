@@ -0,0 +1,37 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+var author = &Author{Name: "Tom Cat"}
+
+func TestBlog(t *testing.T) {
+	posts := []*Post{
+		{Title: "First", Author: author},
+		{Title: "Second", Author: author},
+	}
+
+	html, err := Blog(posts, posts[0])
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<article class='Card'><h2 id='title'>First</h2><span class='small'>TC</span></article><ul><li>First</li><li>Second</li></ul>")
+}
+
+func TestMethods(t *testing.T) {
+	post := &Post{Title: "<Hello>", Author: author}
+
+	fragment, err := post.CardFragment("title")
+	assert.Nil(t, err)
+	assert.Equal(t, fragment, "<h2 id='title'>&lt;Hello&gt;</h2>")
+	assert.Equal(t, post.Summary(), "<li>&lt;Hello&gt;</li>")
+	assert.Equal(t, Profile(author), author.Card())
+}
+
+func TestMethodError(t *testing.T) {
+	post := &Post{Title: "Anonymous", Author: &Author{}}
+	_, err := post.Card()
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "author has no name")
+}
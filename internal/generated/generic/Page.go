@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+package generic
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Page component
+func Page() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(285)
+	streamPage(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b *strings.Builder) {
+	streamTable(_b, names, strings.ToUpper)
+	streamTable[int](_b, numbers, strconv.Itoa)
+	streamTableWith[int](_b, TableProps[int]{Rows: numbers, Cell: square})
+	streamPair(_b, "Answer", 42)
+	streamPair[string, string](_b, "Question", "Unknown")
+}
@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package generic
+
+import (
+	"strings"
+)
+
+// Pair component
+func Pair[K comparable, V any](key K, value V) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(69)
+	streamPair[K, V](_b, key, value)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPair[K comparable, V any](_b *strings.Builder, key K, value V) {
+	_b.WriteString("<dl id='pair'><dt>")
+	writeEscaped(_b, key)
+	_b.WriteString("</dt><dd>")
+	writeEscaped(_b, value)
+	_b.WriteString("</dd></dl>")
+}
+
+// PairProps contains the parameters of the Pair component.
+type PairProps[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// PairWith renders the Pair component with the given props.
+func PairWith[K comparable, V any](props PairProps[K, V]) string {
+	return Pair[K, V](props.Key, props.Value)
+}
+
+func streamPairWith[K comparable, V any](_b *strings.Builder, props PairProps[K, V]) {
+	streamPair[K, V](_b, props.Key, props.Value)
+}
+
+// PairFragment component
+func PairFragment[K comparable, V any](fragment string, key K, value V) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(69)
+	_err := streamPairFragment[K, V](_b, fragment, key, value)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamPairFragment[K comparable, V any](_b *strings.Builder, fragment string, key K, value V) error {
+	if fragment == "pair" {
+		_b.WriteString("<dl id='pair'><dt>")
+		writeEscaped(_b, key)
+		_b.WriteString("</dt><dd>")
+		writeEscaped(_b, value)
+		_b.WriteString("</dd></dl>")
+	} else {
+		return errUnknownFragment(fragment)
+	}
+	return nil
+}
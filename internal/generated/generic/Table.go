@@ -0,0 +1,43 @@
+//go:build go1.18
+// +build go1.18
+
+package generic
+
+import (
+	"strings"
+)
+
+// Table component
+func Table[T any](rows []T, cell func(T) string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(49)
+	streamTable[T](_b, rows, cell)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamTable[T any](_b *strings.Builder, rows []T, cell func(T) string) {
+	_b.WriteString("<table>")
+	for _, row := range rows {
+		_b.WriteString("<tr><td>")
+		writeEscaped(_b, cell(row))
+		_b.WriteString("</td></tr>")
+	}
+	_b.WriteString("</table>")
+}
+
+// TableProps contains the parameters of the Table component.
+type TableProps[T any] struct {
+	Rows []T
+	Cell func(T) string
+}
+
+// TableWith renders the Table component with the given props.
+func TableWith[T any](props TableProps[T]) string {
+	return Table[T](props.Rows, props.Cell)
+}
+
+func streamTableWith[T any](_b *strings.Builder, props TableProps[T]) {
+	streamTable[T](_b, props.Rows, props.Cell)
+}
@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package generic
+
+import "strconv"
+
+// names and numbers are rendered by the same generic component.
+var (
+	names   = []string{"Tom", "Jerry"}
+	numbers = []int{1, 2, 3}
+)
+
+// square returns the square of the number as a string.
+func square(number int) string {
+	return strconv.Itoa(number * number)
+}
@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package generic
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page(), "<table><tr><td>TOM</td></tr><tr><td>JERRY</td></tr></table>"+
+		"<table><tr><td>1</td></tr><tr><td>2</td></tr><tr><td>3</td></tr></table>"+
+		"<table><tr><td>1</td></tr><tr><td>4</td></tr><tr><td>9</td></tr></table>"+
+		"<dl id='pair'><dt>Answer</dt><dd>42</dd></dl>"+
+		"<dl id='pair'><dt>Question</dt><dd>Unknown</dd></dl>")
+}
+
+func TestInstantiation(t *testing.T) {
+	assert.Equal(t, Table([]float64{0.5}, func(value float64) string { return strconv.FormatFloat(value, 'f', 2, 64) }), "<table><tr><td>0.50</td></tr></table>")
+	assert.Equal(t, TableWith(TableProps[int]{Rows: []int{2}, Cell: square}), Table([]int{2}, square))
+
+	fragment, err := PairFragment("pair", 1, true)
+	assert.Nil(t, err)
+	assert.Equal(t, fragment, Pair(1, true))
+}
+
+func TestRegistry(t *testing.T) {
+	_, exists := Registry["Table"]
+	assert.False(t, exists)
+	assert.NotNil(t, Registry["Page"])
+}
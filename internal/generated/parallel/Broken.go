@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Broken component
+func Broken() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamBroken(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamBroken(_b *strings.Builder) {
+	_b.WriteString("<p>")
+	writeEscaped(_b, explode())
+	_b.WriteString("</p>")
+}
@@ -0,0 +1,31 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Dashboard component
+func Dashboard(labels []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(255)
+	streamDashboard(_b, labels)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamDashboard(_b *strings.Builder, labels []string) {
+	_b.WriteString("<main>")
+	{
+		_r, _ := renderParallel(2,
+			func() (string, error) { return Widget(labels[0]), nil },
+			func() (string, error) { return Widget(labels[1]), nil },
+			func() (string, error) { return Widget(labels[2]), nil },
+			func() (string, error) { return Widget(labels[3]), nil },
+		)
+		for _, _s := range _r {
+			_b.WriteString(_s)
+		}
+	}
+	_b.WriteString("<footer>Dashboard</footer></main>")
+}
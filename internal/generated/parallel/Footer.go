@@ -0,0 +1,16 @@
+package parallel
+
+import (
+	"strings"
+)
+
+const staticFooter = "<footer>Dashboard</footer>"
+
+// Footer component
+func Footer() string {
+	return staticFooter
+}
+
+func streamFooter(_b *strings.Builder) {
+	_b.WriteString(staticFooter)
+}
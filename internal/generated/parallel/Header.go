@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Header component
+func Header(title string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(140)
+	streamHeader(_b, title)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamHeader(_b *strings.Builder, title string) {
+	_b.WriteString("<header><svg viewBox=\"0 0 10 10\"><text x=\"0\" y=\"8\">\"P\" \\ &amp;</text></svg>\n<h1>")
+	writeEscaped(_b, title)
+	_b.WriteString("</h1><i class='icon icon-menu'></i></header>")
+}
@@ -0,0 +1,20 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Home component
+func Home(title string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(197)
+	streamHome(_b, title)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamHome(_b *strings.Builder, title string) {
+	streamHeader(_b, title)
+	_b.WriteString("<main><i class='icon icon-home'></i><p>Welcome</p></main>")
+}
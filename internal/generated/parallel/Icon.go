@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Icon component
+func Icon(name string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(32)
+	streamIcon(_b, name)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamIcon(_b *strings.Builder, name string) {
+	_b.WriteString("<i class='")
+	writeEscaped(_b, "icon icon-"+name)
+	_b.WriteString("'></i>")
+}
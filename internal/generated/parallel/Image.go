@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Image component
+func Image(src string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(28)
+	streamImage(_b, src)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamImage(_b *strings.Builder, src string) {
+	_b.WriteString("<img src='")
+	writeEscaped(_b, src)
+	_b.WriteString("'>")
+}
@@ -0,0 +1,34 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Lookup component
+func Lookup(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	_err := streamLookup(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamLookup(_b *strings.Builder, id int) error {
+	_b.WriteString("<p>")
+	{
+		_v, _err := lookup(id)
+		if _err != nil {
+			return _err
+		}
+		writeEscaped(_b, _v)
+	}
+	_b.WriteString("</p>")
+	return nil
+}
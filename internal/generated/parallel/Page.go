@@ -0,0 +1,32 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Page component
+func Page(blocks []Block) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(77)
+	_err := streamPage(_b, blocks)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamPage(_b *strings.Builder, blocks []Block) error {
+	_b.WriteString("<main>")
+	for _, block := range blocks {
+		if _err := streamDynamic(_b, block.Type, block.Args...); _err != nil {
+			return _err
+		}
+	}
+	_b.WriteString("</main>")
+	return nil
+}
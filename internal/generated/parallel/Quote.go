@@ -0,0 +1,26 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Quote component
+func Quote(text string, author *Author) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(70)
+	streamQuote(_b, text, author)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamQuote(_b *strings.Builder, text string, author *Author) {
+	_b.WriteString("<blockquote>")
+	writeEscaped(_b, text)
+	if author != nil {
+		_b.WriteString("<cite>")
+		writeEscaped(_b, author.Name)
+		_b.WriteString("</cite>")
+	}
+	_b.WriteString("</blockquote>")
+}
@@ -0,0 +1,37 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Report component
+func Report(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(89)
+	_err := streamReport(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func streamReport(_b *strings.Builder, id int) error {
+	{
+		_r, _err := renderParallel(2,
+			func() (string, error) { return Widget("summary"), nil },
+			func() (string, error) { return Details(id) },
+		)
+		if _err != nil {
+			return _err
+		}
+		for _, _s := range _r {
+			_b.WriteString(_s)
+		}
+	}
+	return nil
+}
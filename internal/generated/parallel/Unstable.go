@@ -0,0 +1,27 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Unstable component
+func Unstable() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(89)
+	streamUnstable(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamUnstable(_b *strings.Builder) {
+	{
+		_r, _ := renderParallel(2,
+			func() (string, error) { return Widget("a"), nil },
+			func() (string, error) { return Broken(), nil },
+		)
+		for _, _s := range _r {
+			_b.WriteString(_s)
+		}
+	}
+}
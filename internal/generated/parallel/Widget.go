@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// Widget component
+func Widget(label string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(50)
+	streamWidget(_b, label)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamWidget(_b *strings.Builder, label string) {
+	_b.WriteString("<section class='widget'>")
+	writeEscaped(_b, track(label))
+	_b.WriteString("</section>")
+}
@@ -0,0 +1,21 @@
+package parallel
+
+import (
+	"strings"
+)
+
+// caption component
+func caption(text string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(41)
+	streamCaption(_b, text)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamCaption(_b *strings.Builder, text string) {
+	_b.WriteString("<figcaption>")
+	writeEscaped(_b, text)
+	_b.WriteString("</figcaption>")
+}
@@ -0,0 +1,61 @@
+package parallel
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	active      int64
+	maxActive   int64
+)
+
+// track records how many widgets are rendered at the same time.
+func track(label string) string {
+	current := atomic.AddInt64(&active, 1)
+	defer atomic.AddInt64(&active, -1)
+
+	for {
+		previous := atomic.LoadInt64(&maxActive)
+
+		if current <= previous || atomic.CompareAndSwapInt64(&maxActive, previous, current) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	return label
+}
+
+func explode() string {
+	panic("explode")
+}
+
+func details(id int) (string, error) {
+	if id != 1 {
+		return "", errNotFound
+	}
+
+	return "Details", nil
+}
+
+func lookup(id int) (string, error) {
+	if id != 1 {
+		return "", errNotFound
+	}
+
+	return "Found", nil
+}
+
+// Block is a content block selected at runtime.
+type Block struct {
+	Type string
+	Args []interface{}
+}
+
+// Author is the author of a quote.
+type Author struct {
+	Name string
+}
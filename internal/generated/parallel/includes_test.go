@@ -0,0 +1,19 @@
+package parallel
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestIncludedComponents(t *testing.T) {
+	assert.Equal(t, Icon("home"), "<i class='icon icon-home'></i>")
+	assert.Contains(t, Home("<Home>"), Header("<Home>"))
+}
+
+func TestIncludedFile(t *testing.T) {
+	svg, err := ioutil.ReadFile("shared/logo.svg")
+	assert.Nil(t, err)
+	assert.Equal(t, Header("Pixy"), "<header>"+string(svg)+"<h1>Pixy</h1>"+Icon("menu")+"</header>")
+}
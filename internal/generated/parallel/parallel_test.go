@@ -0,0 +1,34 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestDashboard(t *testing.T) {
+	atomic.StoreInt64(&maxActive, 0)
+	html := Dashboard([]string{"a", "b", "c", "d"})
+	assert.Equal(t, html, "<main><section class='widget'>a</section><section class='widget'>b</section><section class='widget'>c</section><section class='widget'>d</section><footer>Dashboard</footer></main>")
+	assert.True(t, atomic.LoadInt64(&maxActive) <= 2)
+}
+
+func TestPanic(t *testing.T) {
+	defer func() {
+		assert.Equal(t, recover(), "explode")
+	}()
+
+	Unstable()
+	t.Fail()
+}
+
+func TestReport(t *testing.T) {
+	html, err := Report(1)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<section class='widget'>summary</section><p>Details</p>")
+
+	html, err = Report(2)
+	assert.Equal(t, err, errNotFound)
+	assert.Equal(t, html, "")
+}
@@ -0,0 +1,82 @@
+package parallel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	quote := Registry["Quote"]
+	assert.NotNil(t, quote)
+	assert.Equal(t, quote.Name, "Quote")
+	assert.Equal(t, len(quote.Parameters), 2)
+	assert.Equal(t, quote.Parameters[0].Name, "text")
+	assert.Equal(t, quote.Parameters[0].Type, reflect.TypeOf(""))
+	assert.Equal(t, quote.Parameters[1].Type, reflect.TypeOf(&Author{}))
+	assert.False(t, quote.ReturnsError)
+	assert.True(t, Registry["Lookup"].ReturnsError)
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render("Quote", "Hello", &Author{Name: "Eve"})
+	assert.Nil(t, err)
+	assert.Equal(t, html, Quote("Hello", &Author{Name: "Eve"}))
+
+	html, err = Render("Quote", "Hello", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<blockquote>Hello</blockquote>")
+
+	html, err = Render("Lookup", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<p>Found</p>")
+}
+
+func TestRenderErrors(t *testing.T) {
+	_, err := Render("Unknown")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown component Unknown")
+
+	_, err = Render("Image")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "needs 1 arguments but got 0")
+
+	_, err = Render("Image", 42)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot use int as string in parameter src")
+
+	_, err = Render("Image", nil)
+	assert.NotNil(t, err)
+
+	_, err = Render("Lookup", 2)
+	assert.Equal(t, err, errNotFound)
+}
+
+func TestDynamic(t *testing.T) {
+	html, err := Page([]Block{
+		{Type: "Image", Args: []interface{}{"/a.png"}},
+		{Type: "Quote", Args: []interface{}{"Hi", &Author{Name: "Bob"}}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<main><img src='/a.png'><blockquote>Hi<cite>Bob</cite></blockquote></main>")
+
+	html, err = Page([]Block{{Type: "Lookup", Args: []interface{}{2}}})
+	assert.Equal(t, err, errNotFound)
+	assert.Equal(t, html, "")
+
+	_, err = Page([]Block{{Type: "Missing"}})
+	assert.NotNil(t, err)
+}
+
+func TestDynamicPrivate(t *testing.T) {
+	html, err := Page([]Block{{Type: "caption", Args: []interface{}{"Note"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<main><figcaption>Note</figcaption></main>")
+
+	assert.Nil(t, Registry["caption"])
+	_, err = Render("caption", "Note")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown component caption")
+}
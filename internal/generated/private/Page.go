@@ -0,0 +1,28 @@
+package private
+
+import (
+	"strings"
+)
+
+// Page component
+func Page(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(235)
+	StreamPage(_b, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func StreamPage(_b *strings.Builder, items []string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		streamItem(_b, item)
+	}
+	_b.WriteString("</ul>")
+	streamButton(_b, "Save")
+	StreamSaveButton(_b, "Explicit")
+	_b.WriteString("<span class='badge'>New</span>")
+	StreamSaveButton(_b, "Implicit")
+	_b.WriteString("<button>Element</button>")
+}
@@ -0,0 +1,19 @@
+package private
+
+import (
+	"strings"
+)
+
+// SaveButton component
+func SaveButton(label string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(49)
+	StreamSaveButton(_b, label)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func StreamSaveButton(_b *strings.Builder, label string) {
+	streamButton(_b, label)
+}
@@ -0,0 +1,16 @@
+package private
+
+import (
+	"strings"
+)
+
+const staticbadge = "<span class='badge'>New</span>"
+
+// badge component
+func badge() string {
+	return staticbadge
+}
+
+func streamBadge(_b *strings.Builder) {
+	_b.WriteString(staticbadge)
+}
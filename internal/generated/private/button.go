@@ -0,0 +1,21 @@
+package private
+
+import (
+	"strings"
+)
+
+// button component
+func button(label string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(49)
+	streamButton(_b, label)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamButton(_b *strings.Builder, label string) {
+	_b.WriteString("<button class='private'>")
+	writeEscaped(_b, label)
+	_b.WriteString("</button>")
+}
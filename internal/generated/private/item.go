@@ -0,0 +1,21 @@
+package private
+
+import (
+	"strings"
+)
+
+// item component
+func item(text string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(25)
+	streamItem(_b, text)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamItem(_b *strings.Builder, text string) {
+	_b.WriteString("<li>")
+	writeEscaped(_b, text)
+	_b.WriteString("</li>")
+}
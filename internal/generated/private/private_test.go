@@ -0,0 +1,35 @@
+package private
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestPrivateComponents(t *testing.T) {
+	assert.Equal(t, button("Save"), "<button class='private'>Save</button>")
+	assert.Equal(t, SaveButton("Save"), button("Save"))
+	assert.Equal(t, badge(), "<span class='badge'>New</span>")
+}
+
+func TestExplicitCalls(t *testing.T) {
+	html := Page([]string{"A", "B"})
+	assert.Equal(t, html, "<ul><li>A</li><li>B</li></ul>"+button("Save")+button("Explicit")+badge()+button("Implicit")+"<button>Element</button>")
+}
+
+func TestStream(t *testing.T) {
+	output := strings.Builder{}
+	StreamPage(&output, nil)
+	assert.Equal(t, output.String(), Page(nil))
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render("SaveButton", "Save")
+	assert.Nil(t, err)
+	assert.Equal(t, html, button("Save"))
+
+	_, err = Render("button", "Save")
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "unknown component button")
+}
@@ -0,0 +1,21 @@
+package props
+
+import (
+	"strings"
+)
+
+// Badge component
+func Badge(count int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(43)
+	streamBadge(_b, count)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamBadge(_b *strings.Builder, count int) {
+	_b.WriteString("<span class='badge'>")
+	writeEscaped(_b, count)
+	_b.WriteString("</span>")
+}
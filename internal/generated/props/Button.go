@@ -0,0 +1,31 @@
+package props
+
+import (
+	"strings"
+)
+
+// Button component
+func Button(label string, kind string, disabled bool) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(125)
+	streamButton(_b, label, kind, disabled)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamButton(_b *strings.Builder, label string, kind string, disabled bool) {
+	if disabled {
+		_b.WriteString("<button class='")
+		writeEscaped(_b, "button "+kind)
+		_b.WriteString("' disabled>")
+		writeEscaped(_b, label)
+		_b.WriteString("</button>")
+	} else {
+		_b.WriteString("<button class='")
+		writeEscaped(_b, "button "+kind)
+		_b.WriteString("'>")
+		writeEscaped(_b, label)
+		_b.WriteString("</button>")
+	}
+}
@@ -0,0 +1,48 @@
+package props
+
+import (
+	"strings"
+)
+
+// Card component
+func Card(title string, subtitle string, count int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(92)
+	streamCard(_b, title, subtitle, count)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamCard(_b *strings.Builder, title string, subtitle string, count int) {
+	_b.WriteString("<section>")
+	if title != "" {
+		_b.WriteString("<h2>")
+		writeEscaped(_b, title)
+		_b.WriteString("</h2>")
+	}
+	if subtitle != "" {
+		_b.WriteString("<h3>")
+		writeEscaped(_b, subtitle)
+		_b.WriteString("</h3>")
+	}
+	_b.WriteString("<p>")
+	writeEscaped(_b, count)
+	_b.WriteString("</p></section>")
+}
+
+// CardProps contains the parameters of the Card component.
+type CardProps struct {
+	Title    string
+	Subtitle string
+	Count    int
+}
+
+// CardWith renders the Card component with the given props.
+func CardWith(props CardProps) string {
+	return Card(props.Title, props.Subtitle, props.Count)
+}
+
+func streamCardWith(_b *strings.Builder, props CardProps) {
+	streamCard(_b, props.Title, props.Subtitle, props.Count)
+}
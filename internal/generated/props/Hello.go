@@ -0,0 +1,38 @@
+package props
+
+import (
+	"strings"
+)
+
+// Hello component
+func Hello(person string, magicNumber int) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(48)
+	streamHello(_b, person, magicNumber)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamHello(_b *strings.Builder, person string, magicNumber int) {
+	_b.WriteString("<h1>")
+	writeEscaped(_b, "Hello "+person)
+	_b.WriteString("</h1><p>")
+	writeEscaped(_b, magicNumber)
+	_b.WriteString("</p>")
+}
+
+// HelloProps contains the parameters of the Hello component.
+type HelloProps struct {
+	Person      string
+	MagicNumber int
+}
+
+// HelloWith renders the Hello component with the given props.
+func HelloWith(props HelloProps) string {
+	return Hello(props.Person, props.MagicNumber)
+}
+
+func streamHelloWith(_b *strings.Builder, props HelloProps) {
+	streamHello(_b, props.Person, props.MagicNumber)
+}
@@ -0,0 +1,22 @@
+package props
+
+import (
+	"strings"
+)
+
+// Page component
+func Page() string {
+	_b := acquireStringsBuilder()
+	_b.Grow(226)
+	streamPage(_b)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b *strings.Builder) {
+	_b.WriteString("<main>")
+	streamCardWith(_b, CardProps{Title: "Hello", Count: 1 + 2})
+	streamCardWith(_b, CardProps{Subtitle: "Tom, Jerry & friends"})
+	_b.WriteString("<h1>Hello World</h1><p>42</p></main>")
+}
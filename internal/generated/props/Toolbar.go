@@ -0,0 +1,25 @@
+package props
+
+import (
+	"strings"
+)
+
+// Toolbar component
+func Toolbar(save string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(481)
+	streamToolbar(_b, save)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamToolbar(_b *strings.Builder, save string) {
+	_b.WriteString("<nav>")
+	streamButton(_b, "Save", "primary", false)
+	streamButton(_b, save, "default", false)
+	streamButton(_b, "Delete", "default", true)
+	streamIcon(_b, "link")
+	streamBadge(_b, 3)
+	_b.WriteString("<a href='/'>Home</a></nav>")
+}
@@ -0,0 +1,14 @@
+package props
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestMacros(t *testing.T) {
+	html := Toolbar("<Save>")
+	assert.Equal(t, html, "<nav>"+Button("Save", "primary", false)+Button("<Save>", "default", false)+Button("Delete", "default", true)+Icon("link")+Badge(3)+"<a href='/'>Home</a></nav>")
+	assert.Contains(t, html, "<button class='button primary'>Save</button>")
+	assert.Contains(t, html, "<button class='button default' disabled>Delete</button>")
+}
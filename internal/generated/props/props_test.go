@@ -0,0 +1,16 @@
+package props
+
+import (
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page(), "<main><section><h2>Hello</h2><p>3</p></section><section><h3>Tom, Jerry &amp; friends</h3><p>0</p></section><h1>Hello World</h1><p>42</p></main>")
+}
+
+func TestWith(t *testing.T) {
+	assert.Equal(t, CardWith(CardProps{Title: "Title", Count: 2}), Card("Title", "", 2))
+	assert.Equal(t, HelloWith(HelloProps{Person: "Props", MagicNumber: 7}), Hello("Props", 7))
+}
@@ -0,0 +1,36 @@
+package stream
+
+import (
+	"io"
+	"strings"
+)
+
+// Card component
+func Card(text string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(103)
+	streamCard(_b, text)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamCard(_w io.Writer, text string) {
+	_b := toWriter(_w)
+	_r := beginDeferred(_b)
+	defer endDeferred(_b, _r)
+	_b.WriteString("<article>")
+	{
+		_id := deferContent(_b, func(_b *strings.Builder) error {
+			_b.WriteString("<p>")
+			writeEscaped(_b, text)
+			_b.WriteString("</p>")
+			return nil
+		})
+		_b.WriteString("<div id='pixy-deferred-")
+		_b.WriteString(_id)
+		_b.WriteString("'><p>Loading</p></div>")
+	}
+	_b.WriteString("</article>")
+	finishDeferred(_b, _r)
+}
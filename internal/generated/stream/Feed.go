@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"io"
+	"strings"
+)
+
+// Feed component
+func Feed(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(184)
+	streamFeed(_b, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamFeed(_w io.Writer, items []string) {
+	_b := toWriter(_w)
+	_r := beginDeferred(_b)
+	defer endDeferred(_b, _r)
+	_b.WriteString("<!DOCTYPE html><html><head><title>Deferred</title></head>")
+	flush(_b)
+	_b.WriteString("<body>")
+	for _, item := range items {
+		{
+			item := item
+			_id := deferContent(_b, func(_b *strings.Builder) error {
+				streamSlow(_b, item)
+				return nil
+			})
+			_b.WriteString("<div id='pixy-deferred-")
+			_b.WriteString(_id)
+			_b.WriteString("'><p>Loading</p></div>")
+		}
+	}
+	_b.WriteString("<footer>Footer</footer>")
+	flushDeferred(_b)
+	_b.WriteString("</body></html>")
+	finishDeferred(_b, _r)
+}
@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"io"
+)
+
+// List component
+func List(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(34)
+	streamList(_b, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamList(_w io.Writer, items []string) {
+	_b := toWriter(_w)
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
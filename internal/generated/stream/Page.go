@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"io"
+)
+
+// Page component
+func Page(title string, items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(152)
+	streamPage(_b, title, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_w io.Writer, title string, items []string) {
+	_b := toWriter(_w)
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, title)
+	_b.WriteString("</title></head>")
+	flush(_b)
+	_b.WriteString("<body>")
+	streamList(_b, items)
+	flush(_b)
+	_b.WriteString("<footer>")
+	writeEscaped(_b, len(items))
+	_b.WriteString("</footer></body></html>")
+}
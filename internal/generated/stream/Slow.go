@@ -0,0 +1,22 @@
+package stream
+
+import (
+	"io"
+)
+
+// Slow component
+func Slow(item string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(23)
+	streamSlow(_b, item)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamSlow(_w io.Writer, item string) {
+	_b := toWriter(_w)
+	_b.WriteString("<p>")
+	writeEscaped(_b, wait(item))
+	_b.WriteString("</p>")
+}
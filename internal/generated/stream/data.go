@@ -0,0 +1,12 @@
+package stream
+
+import "time"
+
+// release is closed to let the deferred content finish rendering.
+var release = make(chan struct{})
+
+func wait(item string) string {
+	<-release
+	time.Sleep(time.Millisecond)
+	return item
+}
@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akyoto/assert"
+)
+
+var ids = regexp.MustCompile(`pixy-deferred-[0-9]+`)
+
+// releaseRecorder records the body at every flush and lets
+// the deferred content finish after the first flush in the body.
+type releaseRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (recorder *releaseRecorder) Flush() {
+	recorder.flushed = append(recorder.flushed, recorder.Body.String())
+
+	if len(recorder.flushed) == 2 {
+		close(release)
+	}
+}
+
+func TestDeferred(t *testing.T) {
+	recorder := &releaseRecorder{ResponseRecorder: httptest.NewRecorder()}
+	streamFeed(recorder, []string{"a", "b"})
+
+	// The rest of the document is sent before the deferred content
+	shell := "<!DOCTYPE html><html><head><title>Deferred</title></head><body><div id='pixy-deferred'><p>Loading</p></div><div id='pixy-deferred'><p>Loading</p></div><footer>Footer</footer>"
+	assert.Equal(t, len(recorder.flushed), 2)
+	assert.Equal(t, ids.ReplaceAllString(recorder.flushed[1], "pixy-deferred"), shell)
+
+	// The deferred content is written in order
+	body := ids.ReplaceAllString(recorder.Body.String(), "pixy-deferred")
+	assert.True(t, strings.HasPrefix(body, shell))
+	assert.True(t, strings.HasSuffix(body, "</body></html>"))
+	first := strings.Index(body, "<template id='pixy-deferred-content'><p>a</p></template><script>")
+	second := strings.Index(body, "<template id='pixy-deferred-content'><p>b</p></template><script>")
+	assert.True(t, first > 0)
+	assert.True(t, second > first)
+
+	// Each script replaces its own placeholder
+	matches := ids.FindAllString(recorder.Body.String(), -1)
+	assert.Equal(t, len(matches), 8)
+	assert.Equal(t, matches[0], matches[2])
+	assert.Equal(t, matches[0]+"-content", strings.Split(recorder.Body.String()[strings.Index(recorder.Body.String(), "<template id='")+len("<template id='"):], "'")[0])
+}
+
+func TestDeferredWithoutBody(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		// Components without a body element write the deferred content at the end
+		card := ids.ReplaceAllString(Card("SECRET"), "pixy-deferred")
+		assert.True(t, strings.HasPrefix(card, "<article><div id='pixy-deferred'><p>Loading</p></div></article><template id='pixy-deferred-content'><p>SECRET</p></template><script>"))
+
+		// The content is never written to the output of the next render
+		assert.Equal(t, Feed(nil), "<!DOCTYPE html><html><head><title>Deferred</title></head><body><footer>Footer</footer></body></html>")
+	}
+
+	// The goroutines rendering the deferred content finish
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.True(t, runtime.NumGoroutine() <= goroutines)
+}
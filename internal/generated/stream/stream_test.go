@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+const expected = "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana &amp; Cherry</li></ul><footer>2</footer></body></html>"
+
+var items = []string{"Apple", "Banana & Cherry"}
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page("Fruits", items), expected)
+}
+
+// onlyWriter hides every method of the response except Write.
+type onlyWriter struct {
+	response http.ResponseWriter
+}
+
+func (writer onlyWriter) Write(p []byte) (int, error) {
+	return writer.response.Write(p)
+}
+
+func TestStreamPage(t *testing.T) {
+	response := httptest.NewRecorder()
+	streamPage(response, "Fruits", items)
+	assert.Equal(t, response.Body.String(), expected)
+
+	response = httptest.NewRecorder()
+	streamPage(onlyWriter{response}, "Fruits", items)
+	assert.Equal(t, response.Body.String(), expected)
+}
+
+// flushRecorder records the body at every flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (recorder *flushRecorder) Flush() {
+	recorder.flushed = append(recorder.flushed, recorder.Body.String())
+	recorder.ResponseRecorder.Flush()
+}
+
+func TestFlush(t *testing.T) {
+	recorder := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	streamPage(recorder, "Fruits", items)
+
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, len(recorder.flushed), 2)
+	assert.Equal(t, recorder.flushed[0], "<!DOCTYPE html><html><head><title>Fruits</title></head>")
+	assert.Equal(t, recorder.flushed[1], "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana &amp; Cherry</li></ul>")
+	assert.Equal(t, recorder.Body.String(), expected)
+}
+
+func TestFlushHandler(t *testing.T) {
+	handler := http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		streamPage(response, "Fruits", items)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, recorder.Body.String(), expected)
+}
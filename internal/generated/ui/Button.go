@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"strings"
+)
+
+// Button component
+func Button(label string, kind string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(58)
+	StreamButton(_b, label, kind)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func StreamButton(_b *strings.Builder, label string, kind string) {
+	_b.WriteString("<button class='")
+	writeEscaped(_b, "button "+kind)
+	_b.WriteString("'>")
+	writeEscaped(_b, label)
+	_b.WriteString("</button>")
+}
+
+// ButtonProps contains the parameters of the Button component.
+type ButtonProps struct {
+	Label string
+	Kind  string
+}
+
+// ButtonWith renders the Button component with the given props.
+func ButtonWith(props ButtonProps) string {
+	return Button(props.Label, props.Kind)
+}
+
+func StreamButtonWith(_b *strings.Builder, props ButtonProps) {
+	StreamButton(_b, props.Label, props.Kind)
+}
+
+// NewButtonProps returns the props of the Button component with the default values.
+func NewButtonProps() ButtonProps {
+	return ButtonProps{Kind: "primary"}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+)
+
+// Card component
+func Card(title string, body string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(80)
+	StreamCard(_b, title, body)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func StreamCard(_b *strings.Builder, title string, body string) {
+	_b.WriteString("<section class='card'><h2>")
+	writeEscaped(_b, title)
+	_b.WriteString("</h2><p>")
+	writeEscaped(_b, body)
+	_b.WriteString("</p></section>")
+}
+
+// CardProps contains the parameters of the Card component.
+type CardProps struct {
+	Title string
+	Body  string
+}
+
+// CardWith renders the Card component with the given props.
+func CardWith(props CardProps) string {
+	return Card(props.Title, props.Body)
+}
+
+func StreamCardWith(_b *strings.Builder, props CardProps) {
+	StreamCard(_b, props.Title, props.Body)
+}
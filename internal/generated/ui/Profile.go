@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+)
+
+// Profile component
+func Profile(id int) (string, error) {
+	_b := acquireStringsBuilder()
+	_b.Grow(39)
+	_err := StreamProfile(_b, id)
+
+	if _err != nil {
+		releaseStringsBuilder(_b)
+		return "", _err
+	}
+
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s, nil
+}
+
+func StreamProfile(_b *strings.Builder, id int) error {
+	_b.WriteString("<p class='profile'>")
+	{
+		_v, _err := profileName(id)
+		if _err != nil {
+			return _err
+		}
+		writeEscaped(_b, _v)
+	}
+	_b.WriteString("</p>")
+	return nil
+}
+
+// ProfileProps contains the parameters of the Profile component.
+type ProfileProps struct {
+	Id int
+}
+
+// ProfileWith renders the Profile component with the given props.
+func ProfileWith(props ProfileProps) (string, error) {
+	return Profile(props.Id)
+}
+
+func StreamProfileWith(_b *strings.Builder, props ProfileProps) error {
+	return StreamProfile(_b, props.Id)
+}
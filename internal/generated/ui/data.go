@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrUnknownProfile is returned for profiles that don't exist.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+func profileName(id int) (string, error) {
+	if id <= 0 {
+		return "", ErrUnknownProfile
+	}
+
+	return "User " + strconv.Itoa(id), nil
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akyoto/assert"
+)
+
+func TestStream(t *testing.T) {
+	output := strings.Builder{}
+	StreamButton(&output, "Save", "primary")
+	assert.Equal(t, output.String(), Button("Save", "primary"))
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render("Button", "Save")
+	assert.Nil(t, err)
+	assert.Equal(t, html, Button("Save", "primary"))
+}
+
+func TestProfile(t *testing.T) {
+	html, err := Profile(1)
+	assert.Nil(t, err)
+	assert.Equal(t, html, "<p class='profile'>User 1</p>")
+
+	_, err = Profile(0)
+	assert.Equal(t, err, ErrUnknownProfile)
+}
@@ -0,0 +1,25 @@
+package writer
+
+import (
+	"github.com/aerogo/pixy"
+)
+
+// List component
+func List(items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(34)
+	streamList(_b, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamList(_b pixy.Writer, items []string) {
+	_b.WriteString("<ul>")
+	for _, item := range items {
+		_b.WriteString("<li>")
+		writeEscaped(_b, item)
+		_b.WriteString("</li>")
+	}
+	_b.WriteString("</ul>")
+}
@@ -0,0 +1,23 @@
+package writer
+
+import (
+	"github.com/aerogo/pixy"
+)
+
+// Page component
+func Page(title string, items []string) string {
+	_b := acquireStringsBuilder()
+	_b.Grow(119)
+	streamPage(_b, title, items)
+	_s := _b.String()
+	releaseStringsBuilder(_b)
+	return _s
+}
+
+func streamPage(_b pixy.Writer, title string, items []string) {
+	_b.WriteString("<!DOCTYPE html><html><head><title>")
+	writeEscaped(_b, title)
+	_b.WriteString("</title></head><body>")
+	streamList(_b, items)
+	_b.WriteString("</body></html>")
+}
@@ -0,0 +1,35 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/pixy"
+	"github.com/akyoto/assert"
+)
+
+const expected = "<!DOCTYPE html><html><head><title>Fruits</title></head><body><ul><li>Apple</li><li>Banana &amp; Cherry</li></ul></body></html>"
+
+var items = []string{"Apple", "Banana & Cherry"}
+
+func TestPage(t *testing.T) {
+	assert.Equal(t, Page("Fruits", items), expected)
+}
+
+func TestStreamPage(t *testing.T) {
+	builder := &strings.Builder{}
+	buffer := &bytes.Buffer{}
+	buffered := &strings.Builder{}
+	bufferedWriter := bufio.NewWriter(buffered)
+
+	for _, writer := range []pixy.Writer{builder, buffer, bufferedWriter} {
+		streamPage(writer, "Fruits", items)
+	}
+
+	assert.Nil(t, bufferedWriter.Flush())
+	assert.Equal(t, builder.String(), expected)
+	assert.Equal(t, buffer.String(), expected)
+	assert.Equal(t, buffered.String(), expected)
+}
@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package interp_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/pixy/internal/generated/generic"
+)
+
+func TestConformanceGeneric(t *testing.T) {
+	template := parse(t, "generic/components.pixy").Funcs(map[string]interface{}{
+		"strings.ToUpper": strings.ToUpper,
+		"strconv.Itoa":    strconv.Itoa,
+		"names":           []string{"Tom", "Jerry"},
+		"numbers":         []int{1, 2, 3},
+		"square":          func(number int) string { return strconv.Itoa(number * number) },
+	})
+
+	conform(t, template, "Page", nil, generic.Page())
+}
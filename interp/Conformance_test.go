@@ -0,0 +1,165 @@
+package interp_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/pixy"
+	"github.com/aerogo/pixy/internal/generated/app"
+	"github.com/aerogo/pixy/internal/generated/builder"
+	"github.com/aerogo/pixy/internal/generated/defaults"
+	"github.com/aerogo/pixy/internal/generated/parallel"
+	"github.com/aerogo/pixy/internal/generated/private"
+	"github.com/aerogo/pixy/internal/generated/props"
+	"github.com/aerogo/pixy/internal/generated/stream"
+	"github.com/aerogo/pixy/internal/generated/ui"
+	"github.com/aerogo/pixy/internal/generated/writer"
+	"github.com/aerogo/pixy/interp"
+	"github.com/akyoto/assert"
+)
+
+// parse parses a template in internal/generated.
+func parse(t *testing.T, path string) *interp.Template {
+	template := interp.New()
+	assert.Nil(t, template.ParseFile("../internal/generated/"+path))
+	return template
+}
+
+// conform checks that the interpreter renders the same output as the compiled component.
+func conform(t *testing.T, template *interp.Template, name string, parameters map[string]interface{}, compiled string) {
+	output, err := template.Render(name, parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, output, compiled)
+}
+
+func TestConformanceBuilder(t *testing.T) {
+	template := parse(t, "builder/components.pixy")
+
+	conform(t, template, "Hello", map[string]interface{}{"person": "<World>"}, builder.Hello("<World>"))
+	conform(t, template, "Layout", map[string]interface{}{"title": "Tom & Jerry"}, builder.Layout("Tom & Jerry"))
+	conform(t, template, "Counter", map[string]interface{}{"count": 5}, builder.Counter(5))
+	conform(t, template, "Footer", nil, builder.Footer())
+	conform(t, template, "Icon", nil, builder.Icon())
+	conform(t, template, "Badge", map[string]interface{}{"label": "'new'"}, builder.Badge("'new'"))
+	conform(t, template, "Tags", nil, builder.Tags())
+	conform(t, template, "Numbers", nil, builder.Numbers())
+	conform(t, template, "Greetings", nil, builder.Greetings())
+	conform(t, template, "Literals", nil, builder.Literals())
+
+	for _, value := range []interface{}{"<'\">&", 42, int64(-7), uint8(255), 1.5, float32(0.1), 1e21, true, nil, []int{1, 2}} {
+		conform(t, template, "Escaped", map[string]interface{}{"value": value}, builder.Escaped(value))
+	}
+}
+
+func TestConformanceDeferred(t *testing.T) {
+	template := parse(t, "stream/deferred.pixy")
+	ids := regexp.MustCompile(`pixy-deferred-[0-9]+`)
+	output, err := template.Render("Card", map[string]interface{}{"text": "Hello"})
+	assert.Nil(t, err)
+	assert.Equal(t, ids.ReplaceAllString(output, "pixy-deferred"), ids.ReplaceAllString(stream.Card("Hello"), "pixy-deferred"))
+}
+
+func TestConformanceWriter(t *testing.T) {
+	template := parse(t, "writer/components.pixy")
+	items := []string{"Apple", "Banana & Cherry"}
+
+	conform(t, template, "Page", map[string]interface{}{"title": "Fruits", "items": items}, writer.Page("Fruits", items))
+	conform(t, template, "List", map[string]interface{}{"items": []string{}}, writer.List(nil))
+}
+
+func TestConformanceFragments(t *testing.T) {
+	template := parse(t, "builder/fragments.pixy")
+
+	for _, post := range []*builder.Post{
+		{Title: "Hello", Likes: 2, Comments: []string{"First", "Second <3"}},
+		{Title: "Empty", Likes: 1},
+	} {
+		conform(t, template, "Postable", map[string]interface{}{"post": post}, builder.Postable(post))
+	}
+}
+
+func TestConformanceRegistry(t *testing.T) {
+	template := parse(t, "parallel/registry.pixy")
+	blocks := []parallel.Block{
+		{Type: "Image", Args: []interface{}{"/a.png"}},
+		{Type: "Quote", Args: []interface{}{"Hi", &parallel.Author{Name: "Bob"}}},
+	}
+
+	compiled, err := parallel.Page(blocks)
+	assert.Nil(t, err)
+	conform(t, template, "Page", map[string]interface{}{"blocks": blocks}, compiled)
+}
+
+func TestConformanceProps(t *testing.T) {
+	conform(t, parse(t, "props/components.pixy"), "Page", nil, props.Page())
+}
+
+func TestConformanceDefaults(t *testing.T) {
+	template := parse(t, "defaults/components.pixy").Funcs(map[string]interface{}{
+		"strconv.Itoa":       strconv.Itoa,
+		"strconv.FormatBool": strconv.FormatBool,
+		"names":              []string{"Spike", "Tyke"},
+	})
+
+	conform(t, template, "Page", nil, defaults.Page())
+	conform(t, template, "Badge", map[string]interface{}{"count": 100}, defaults.Badge(100, "new", 99))
+	conform(t, template, "List", map[string]interface{}{"items": []string{"Tom"}}, defaults.List("Tom"))
+}
+
+func TestConformanceMethods(t *testing.T) {
+	template := parse(t, "defaults/methods.pixy")
+	author := &defaults.Author{Name: "Tom Cat"}
+	posts := []*defaults.Post{{Title: "First", Author: author}, {Title: "<Second>", Author: author}}
+
+	compiled, err := defaults.Blog(posts, posts[1])
+	assert.Nil(t, err)
+	conform(t, template, "Blog", map[string]interface{}{"posts": posts, "featured": posts[1]}, compiled)
+	conform(t, template, "Post.Summary", map[string]interface{}{"post": posts[0]}, posts[0].Summary())
+	conform(t, template, "Profile", map[string]interface{}{"author": author}, defaults.Profile(author))
+}
+
+func TestConformancePackages(t *testing.T) {
+	template := parse(t, "app/components.pixy").Funcs(map[string]interface{}{
+		"ui.Button":         ui.Button,
+		"ui.ButtonWith":     ui.ButtonWith,
+		"ui.NewButtonProps": ui.NewButtonProps,
+		"ui.Card":           ui.Card,
+		"str.ToUpper":       strings.ToUpper,
+		"fmt.Sprintf":       fmt.Sprintf,
+	})
+
+	conform(t, template, "Page", map[string]interface{}{"title": "Cart", "count": 3}, app.Page("Cart", 3))
+}
+
+func TestConformancePrivate(t *testing.T) {
+	template := parse(t, "private/components.pixy")
+	items := []string{"A", "<B>"}
+
+	conform(t, template, "Page", map[string]interface{}{"items": items}, private.Page(items))
+	conform(t, template, "SaveButton", map[string]interface{}{"label": "Save"}, private.SaveButton("Save"))
+}
+
+func TestConformanceIncludes(t *testing.T) {
+	template := parse(t, "parallel/includes.pixy")
+
+	conform(t, template, "Home", map[string]interface{}{"title": "<Home>"}, parallel.Home("<Home>"))
+	conform(t, template, "Icon", map[string]interface{}{"name": "menu"}, parallel.Icon("menu"))
+}
+
+func TestConformanceFilters(t *testing.T) {
+	template := parse(t, "builder/filters.pixy").RegisterFilter("upper", func(text string) (string, error) {
+		return strings.ToUpper(strings.TrimSpace(text)), nil
+	})
+
+	conform(t, template, "Article", map[string]interface{}{"title": "<Filters>"}, builder.Article("<Filters>"))
+}
+
+func TestConformanceMacros(t *testing.T) {
+	template := interp.New().Macros(map[string]*pixy.Macro{"badge": {Component: "Badge"}})
+	assert.Nil(t, template.ParseFile("../internal/generated/props/macros.pixy"))
+
+	conform(t, template, "Toolbar", map[string]interface{}{"save": "<Save>"}, props.Toolbar("<Save>"))
+}
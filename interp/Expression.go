@@ -0,0 +1,575 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+)
+
+// scope contains the variables declared in a block.
+type scope struct {
+	variables map[string]reflect.Value
+	parent    *scope
+}
+
+// child returns a new scope for a nested block.
+func (variables *scope) child() *scope {
+	return &scope{
+		variables: map[string]reflect.Value{},
+		parent:    variables,
+	}
+}
+
+// get returns the value of a variable in this or a parent scope.
+func (variables *scope) get(name string) (reflect.Value, bool) {
+	for current := variables; current != nil; current = current.parent {
+		value, exists := current.variables[name]
+
+		if exists {
+			return value, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// set changes the value of an existing variable in this or a parent scope.
+func (variables *scope) set(name string, value reflect.Value) bool {
+	for current := variables; current != nil; current = current.parent {
+		_, exists := current.variables[name]
+
+		if exists {
+			current.variables[name] = value
+			return true
+		}
+	}
+
+	return false
+}
+
+// parse returns the parsed expression. Expressions are parsed only once.
+func (template *Template) parse(source string) (ast.Expr, error) {
+	cached, exists := template.expressions.Load(source)
+
+	if exists {
+		return cached.(ast.Expr), nil
+	}
+
+	expression, err := parser.ParseExpr(source)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %s", source)
+	}
+
+	template.expressions.Store(source, expression)
+	return expression, nil
+}
+
+// stringLiteral tells whether an expression is a single string literal.
+// The compiler writes such attribute values without escaping entities.
+func (template *Template) stringLiteral(source string) bool {
+	expression, err := template.parse(source)
+
+	if err != nil {
+		return false
+	}
+
+	literal, isLiteral := expression.(*ast.BasicLit)
+	return isLiteral && literal.Kind == token.STRING
+}
+
+// evaluate returns the value of an expression.
+func (renderer *renderer) evaluate(source string, variables *scope) (reflect.Value, error) {
+	expression, err := renderer.template.parse(source)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return renderer.expression(expression, variables)
+}
+
+// expression returns the value of a parsed expression.
+// Nil values are returned as invalid values.
+func (renderer *renderer) expression(expression ast.Expr, variables *scope) (reflect.Value, error) {
+	switch expression := expression.(type) {
+	case *ast.BasicLit:
+		return literal(expression)
+
+	case *ast.Ident:
+		return renderer.identifier(expression.Name, variables)
+
+	case *ast.ParenExpr:
+		return renderer.expression(expression.X, variables)
+
+	case *ast.SelectorExpr:
+		return renderer.selector(expression, variables)
+
+	case *ast.IndexExpr:
+		collection, err := renderer.expression(expression.X, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		key, err := renderer.expression(expression.Index, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return index(collection, key)
+
+	case *ast.SliceExpr:
+		return renderer.slice(expression, variables)
+
+	case *ast.StarExpr:
+		pointer, err := renderer.expression(expression.X, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if pointer.Kind() != reflect.Ptr || pointer.IsNil() {
+			return reflect.Value{}, fmt.Errorf("invalid indirect")
+		}
+
+		return pointer.Elem(), nil
+
+	case *ast.UnaryExpr:
+		operand, err := renderer.expression(expression.X, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return unary(expression.Op, operand)
+
+	case *ast.BinaryExpr:
+		return renderer.binary(expression, variables)
+
+	case *ast.CompositeLit:
+		return renderer.composite(expression, nil, variables)
+
+	case *ast.CallExpr:
+		results, err := renderer.callExpression(expression, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if len(results) != 1 {
+			return reflect.Value{}, fmt.Errorf("function call with %d results used as a value", len(results))
+		}
+
+		return results[0], nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("unsupported expression %T", expression)
+}
+
+// identifier returns the value of a variable, a global or a predeclared identifier.
+func (renderer *renderer) identifier(name string, variables *scope) (reflect.Value, error) {
+	value, exists := variables.get(name)
+
+	if exists {
+		return value, nil
+	}
+
+	value, exists = renderer.template.globals[name]
+
+	if exists {
+		return value, nil
+	}
+
+	switch name {
+	case "true":
+		return reflect.ValueOf(true), nil
+	case "false":
+		return reflect.ValueOf(false), nil
+	case "nil":
+		return reflect.Value{}, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("undefined: %s", name)
+}
+
+// selector returns a field or method of a value or a qualified global like strings.ToUpper.
+func (renderer *renderer) selector(expression *ast.SelectorExpr, variables *scope) (reflect.Value, error) {
+	name := expression.Sel.Name
+	packageName, isIdentifier := expression.X.(*ast.Ident)
+
+	if isIdentifier {
+		_, isVariable := variables.get(packageName.Name)
+		global, isGlobal := renderer.template.globals[packageName.Name+"."+name]
+
+		if !isVariable && isGlobal {
+			return global, nil
+		}
+	}
+
+	value, err := renderer.expression(expression.X, variables)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return member(value, name)
+}
+
+// slice returns a slice of a slice, an array or a string.
+func (renderer *renderer) slice(expression *ast.SliceExpr, variables *scope) (reflect.Value, error) {
+	collection, err := renderer.expression(expression.X, variables)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	collection = indirect(collection)
+	low := 0
+	high := collection.Len()
+
+	for _, bound := range []struct {
+		expression ast.Expr
+		value      *int
+	}{{expression.Low, &low}, {expression.High, &high}} {
+		if bound.expression == nil {
+			continue
+		}
+
+		value, err := renderer.expression(bound.expression, variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if !isInteger(value) {
+			return reflect.Value{}, fmt.Errorf("non-integer slice index")
+		}
+
+		*bound.value = int(toInt(value))
+	}
+
+	if low < 0 || high > collection.Len() || low > high {
+		return reflect.Value{}, fmt.Errorf("slice bounds out of range [%d:%d]", low, high)
+	}
+
+	return collection.Slice(low, high), nil
+}
+
+// binary returns the result of a binary expression.
+// The logical operators only evaluate the right operand if necessary.
+func (renderer *renderer) binary(expression *ast.BinaryExpr, variables *scope) (reflect.Value, error) {
+	left, err := renderer.expression(expression.X, variables)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if expression.Op == token.LAND || expression.Op == token.LOR {
+		if left.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("operator %s not defined on %s", expression.Op, typeName(left))
+		}
+
+		if left.Bool() == (expression.Op == token.LOR) {
+			return left, nil
+		}
+	}
+
+	right, err := renderer.expression(expression.Y, variables)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return binary(expression.Op, left, right)
+}
+
+// callExpression calls a function, a method or a builtin and returns the results.
+func (renderer *renderer) callExpression(expression *ast.CallExpr, variables *scope) ([]reflect.Value, error) {
+	arguments := make([]reflect.Value, len(expression.Args))
+
+	for index, argument := range expression.Args {
+		value, err := renderer.expression(argument, variables)
+
+		if err != nil {
+			return nil, err
+		}
+
+		arguments[index] = value
+	}
+
+	identifier, isIdentifier := expression.Fun.(*ast.Ident)
+
+	if isIdentifier {
+		_, isVariable := variables.get(identifier.Name)
+		_, isGlobal := renderer.template.globals[identifier.Name]
+
+		if !isVariable && !isGlobal {
+			result, isBuiltin, err := builtin(identifier.Name, arguments)
+
+			if isBuiltin {
+				return []reflect.Value{result}, err
+			}
+		}
+	}
+
+	function, err := renderer.expression(expression.Fun, variables)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return call(function, arguments, expression.Ellipsis.IsValid())
+}
+
+// literal returns the value of a basic literal.
+func literal(expression *ast.BasicLit) (reflect.Value, error) {
+	switch expression.Kind {
+	case token.INT:
+		value, err := strconv.ParseInt(expression.Value, 0, 64)
+		return reflect.ValueOf(int(value)), err
+
+	case token.FLOAT:
+		value, err := strconv.ParseFloat(expression.Value, 64)
+		return reflect.ValueOf(value), err
+
+	case token.STRING:
+		value, err := strconv.Unquote(expression.Value)
+		return reflect.ValueOf(value), err
+
+	case token.CHAR:
+		value, _, _, err := strconv.UnquoteChar(expression.Value[1:len(expression.Value)-1], '\'')
+		return reflect.ValueOf(value), err
+	}
+
+	return reflect.Value{}, fmt.Errorf("unsupported literal %s", expression.Value)
+}
+
+// composite returns the value of a composite literal of a slice, array or map type.
+// Literals with elided types like the inner ones of [][]int{{1}} use the given type.
+func (renderer *renderer) composite(expression *ast.CompositeLit, literalType reflect.Type, variables *scope) (reflect.Value, error) {
+	if expression.Type != nil {
+		var err error
+		literalType, err = typeOf(expression.Type)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if literalType == nil {
+		return reflect.Value{}, fmt.Errorf("missing type in composite literal")
+	}
+
+	if literalType.Kind() == reflect.Ptr {
+		value, err := renderer.composite(expression, literalType.Elem(), variables)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		pointer := reflect.New(literalType.Elem())
+		pointer.Elem().Set(value)
+		return pointer, nil
+	}
+
+	switch literalType.Kind() {
+	case reflect.Map:
+		result := reflect.MakeMapWithSize(literalType, len(expression.Elts))
+
+		for _, element := range expression.Elts {
+			pair, isPair := element.(*ast.KeyValueExpr)
+
+			if !isPair {
+				return reflect.Value{}, fmt.Errorf("missing key in map literal")
+			}
+
+			key, err := renderer.compositeElement(pair.Key, literalType.Key(), variables)
+
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			value, err := renderer.compositeElement(pair.Value, literalType.Elem(), variables)
+
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			result.SetMapIndex(key, value)
+		}
+
+		return result, nil
+
+	case reflect.Slice, reflect.Array:
+		values := map[int]reflect.Value{}
+		length := 0
+		position := 0
+
+		for _, element := range expression.Elts {
+			pair, isPair := element.(*ast.KeyValueExpr)
+
+			if isPair {
+				key, err := renderer.expression(pair.Key, variables)
+
+				if err != nil {
+					return reflect.Value{}, err
+				}
+
+				if !isInteger(key) || toInt(key) < 0 {
+					return reflect.Value{}, fmt.Errorf("index must be non-negative integer constant")
+				}
+
+				position = int(toInt(key))
+				element = pair.Value
+			}
+
+			if _, exists := values[position]; exists {
+				return reflect.Value{}, fmt.Errorf("duplicate index %d in array or slice literal", position)
+			}
+
+			value, err := renderer.compositeElement(element, literalType.Elem(), variables)
+
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			values[position] = value
+			position++
+
+			if position > length {
+				length = position
+			}
+		}
+
+		var result reflect.Value
+
+		if literalType.Kind() == reflect.Array {
+			if length > literalType.Len() {
+				return reflect.Value{}, fmt.Errorf("array index %d out of bounds [0:%d]", length-1, literalType.Len())
+			}
+
+			result = reflect.New(literalType).Elem()
+		} else {
+			result = reflect.MakeSlice(literalType, length, length)
+		}
+
+		for position, value := range values {
+			result.Index(position).Set(value)
+		}
+
+		return result, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("invalid composite literal type %s", literalType)
+}
+
+// compositeElement returns the value of an element of a composite literal converted to the element type.
+func (renderer *renderer) compositeElement(expression ast.Expr, elementType reflect.Type, variables *scope) (reflect.Value, error) {
+	var value reflect.Value
+	var err error
+
+	literal, isLiteral := expression.(*ast.CompositeLit)
+
+	if isLiteral {
+		value, err = renderer.composite(literal, elementType, variables)
+	} else {
+		value, err = renderer.expression(expression, variables)
+	}
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	value = convert(value, elementType.String())
+
+	if !value.IsValid() {
+		return reflect.Zero(elementType), nil
+	}
+
+	if !value.Type().AssignableTo(elementType) {
+		return reflect.Value{}, fmt.Errorf("cannot use %s as %s in composite literal", value.Type(), elementType)
+	}
+
+	return value, nil
+}
+
+// typeOf returns the type of a type expression made of basic types, slices, arrays, maps, pointers and interface{}.
+func typeOf(expression ast.Expr) (reflect.Type, error) {
+	switch expression := expression.(type) {
+	case *ast.Ident:
+		basic, isBasic := basicTypes[expression.Name]
+
+		if isBasic {
+			return basic, nil
+		}
+
+		if expression.Name == "error" {
+			return errorType, nil
+		}
+
+	case *ast.ParenExpr:
+		return typeOf(expression.X)
+
+	case *ast.StarExpr:
+		element, err := typeOf(expression.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.PtrTo(element), nil
+
+	case *ast.InterfaceType:
+		if len(expression.Methods.List) == 0 {
+			return reflect.TypeOf((*interface{})(nil)).Elem(), nil
+		}
+
+	case *ast.ArrayType:
+		element, err := typeOf(expression.Elt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if expression.Len == nil {
+			return reflect.SliceOf(element), nil
+		}
+
+		length, isLiteral := expression.Len.(*ast.BasicLit)
+
+		if isLiteral && length.Kind == token.INT {
+			value, err := strconv.Atoi(length.Value)
+
+			if err == nil {
+				return reflect.ArrayOf(value, element), nil
+			}
+		}
+
+	case *ast.MapType:
+		key, err := typeOf(expression.Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := typeOf(expression.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !key.Comparable() {
+			return nil, fmt.Errorf("invalid map key type %s", key)
+		}
+
+		return reflect.MapOf(key, value), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", types.ExprString(expression))
+}
@@ -0,0 +1,700 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aerogo/pixy"
+)
+
+// renderer renders the components for a single call of Execute.
+type renderer struct {
+	template *Template
+	output   io.Writer
+	deferred []*deferredFragment
+	lastID   int
+	err      error
+}
+
+// deferredFragment is the rendered content of a deferred block.
+type deferredFragment struct {
+	id     string
+	output string
+}
+
+// failure is an error returned by a function called with ?=.
+// It's returned from Execute without any additional information.
+type failure struct {
+	err error
+}
+
+// Error returns the message of the original error.
+func (failure *failure) Error() string {
+	return failure.err.Error()
+}
+
+// component renders a component with the given arguments.
+// The receiver of a method is the first argument.
+func (renderer *renderer) component(component *component, arguments []reflect.Value) error {
+	variables := &scope{variables: make(map[string]reflect.Value, len(arguments))}
+
+	if component.receiver != nil {
+		variables.variables[component.receiver.name] = arguments[0]
+		arguments = arguments[1:]
+	}
+
+	for index, parameter := range component.parameters {
+		variables.variables[parameter.name] = convert(arguments[index], parameter.typeName)
+	}
+
+	err := renderer.children(component.definition.Children, variables)
+
+	if _, isFailure := err.(*failure); isFailure {
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("component %s: %v", component.definition.Name, err)
+	}
+
+	return nil
+}
+
+// children renders a list of nodes.
+func (renderer *renderer) children(nodes []pixy.Node, variables *scope) error {
+	// Tells whether a previous branch of the current if statement has been taken
+	taken := false
+
+	for _, child := range nodes {
+		block, isBlock := child.(*pixy.Block)
+
+		if isBlock && strings.HasPrefix(block.Statement, "else") {
+			if taken {
+				continue
+			}
+
+			if block.Statement == "else" {
+				err := renderer.children(block.Children, variables.child())
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if isBlock && (strings.HasPrefix(block.Statement, "if ") || strings.HasPrefix(block.Statement, "else if ")) {
+			var err error
+			taken, err = renderer.ifBlock(block, variables)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err := renderer.node(child, variables)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// node renders a single node.
+func (renderer *renderer) node(child pixy.Node, variables *scope) error {
+	switch child := child.(type) {
+	case *pixy.Element:
+		return renderer.element(child, variables)
+
+	case *pixy.Call:
+		return renderer.call(child, variables)
+
+	case *pixy.DynamicCall:
+		return renderer.dynamicCall(child, variables)
+
+	case *pixy.Block:
+		return renderer.forBlock(child, variables)
+
+	case *pixy.Each:
+		return renderer.each(child, variables)
+
+	case *pixy.Embed:
+		value, err := renderer.evaluate(child.Expression, variables)
+
+		if err != nil {
+			return err
+		}
+
+		renderer.write(raw(value))
+		return nil
+
+	case *pixy.Flush:
+		renderer.flush()
+		return nil
+
+	case *pixy.Filter:
+		return renderer.filter(child)
+
+	case *pixy.Parallel:
+		return renderer.children(child.Children, variables)
+
+	case *pixy.Deferred:
+		return renderer.deferredBlock(child, variables)
+
+	case *pixy.Fragment:
+		return renderer.children(child.Children, variables)
+	}
+
+	return fmt.Errorf("unknown node %T", child)
+}
+
+// element renders an element, its contents and its children.
+func (renderer *renderer) element(element *pixy.Element, variables *scope) error {
+	if element.Name == "html" {
+		renderer.write("<!DOCTYPE html>")
+	}
+
+	renderer.write("<" + element.Name)
+
+	for _, attribute := range element.Attributes {
+		if attribute.Value == "" {
+			renderer.write(" " + attribute.Name)
+			continue
+		}
+
+		value, err := renderer.evaluate(attribute.Value, variables)
+
+		if err != nil {
+			return err
+		}
+
+		// String literals are only protected against the apostrophes enclosing them
+		if renderer.template.stringLiteral(attribute.Value) {
+			renderer.write(" " + attribute.Name + "='" + strings.Replace(format(value), "'", "&#39;", -1) + "'")
+			continue
+		}
+
+		renderer.write(" " + attribute.Name + "='" + escape(value) + "'")
+	}
+
+	renderer.write(">")
+
+	switch {
+	case element.Fallible:
+		value, err := renderer.fallible(element.Expression, variables)
+
+		if err != nil {
+			return err
+		}
+
+		renderer.writeValue(value, element.Raw)
+
+	case element.Expression != "":
+		value, err := renderer.evaluate(element.Expression, variables)
+
+		if err != nil {
+			return err
+		}
+
+		renderer.writeValue(value, element.Raw)
+
+	case element.Text != "":
+		renderer.write(element.Text)
+	}
+
+	err := renderer.children(element.Children, variables)
+
+	if err != nil {
+		return err
+	}
+
+	if element.Name == "body" {
+		renderer.flushDeferred()
+	}
+
+	if !element.SelfClosing() {
+		renderer.write("</" + element.Name + ">")
+	}
+
+	return nil
+}
+
+// call renders a call to another component.
+func (renderer *renderer) call(call *pixy.Call, variables *scope) error {
+	if call.Receiver != "" {
+		return renderer.methodCall(call, variables)
+	}
+
+	if call.Package != "" {
+		return renderer.packageCall(call, variables)
+	}
+
+	component, exists := renderer.template.components[call.Name]
+
+	if !exists {
+		return fmt.Errorf("unknown component %s", call.Name)
+	}
+
+	names, _ := call.NamedArguments()
+
+	if names != nil {
+		return renderer.namedCall(call, component, variables)
+	}
+
+	values, err := renderer.callArguments(call, component, variables)
+
+	if err != nil {
+		return err
+	}
+
+	return renderer.component(component, values)
+}
+
+// callArguments returns the values of the positional arguments of a call.
+func (renderer *renderer) callArguments(call *pixy.Call, component *component, variables *scope) ([]reflect.Value, error) {
+	values, spread, err := renderer.positional(call, variables)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return renderer.complete(component, values, spread)
+}
+
+// complete returns the arguments of a call with the variadic arguments collected in a slice
+// and the default values of the omitted trailing parameters appended.
+// Spread arguments already pass the variadic arguments as a slice.
+func (renderer *renderer) complete(component *component, values []reflect.Value, spread bool) ([]reflect.Value, error) {
+	parameters := component.parameters
+	count := len(values)
+
+	if component.variadic() && !spread && len(values) >= len(parameters)-1 {
+		fixed := len(parameters) - 1
+		variadic, err := collect(values[fixed:], parameters[fixed].typeName[len("..."):])
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", component.definition.Name, err)
+		}
+
+		values = append(values[:fixed:fixed], variadic)
+	}
+
+	for len(values) < len(parameters) {
+		value, hasDefault := component.definition.Defaults[parameters[len(values)].name]
+
+		if !hasDefault {
+			break
+		}
+
+		result, err := renderer.evaluate(value, &scope{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, result)
+	}
+
+	if len(values) != len(parameters) {
+		return nil, fmt.Errorf("%s needs %d arguments but got %d", component.definition.Name, len(parameters), count)
+	}
+
+	return values, nil
+}
+
+// methodCall renders a call to a component defined as a method of the receiver type.
+func (renderer *renderer) methodCall(call *pixy.Call, variables *scope) error {
+	receiver, err := renderer.evaluate(call.Receiver, variables)
+
+	if err != nil {
+		return err
+	}
+
+	receiver = concrete(receiver)
+
+	if !receiver.IsValid() {
+		return fmt.Errorf("%s is nil", call.Receiver)
+	}
+
+	name := indirect(receiver).Type().Name() + "." + call.Name
+	component, exists := renderer.template.components[name]
+
+	if !exists {
+		return fmt.Errorf("unknown component %s", name)
+	}
+
+	names, _ := call.NamedArguments()
+
+	if names != nil {
+		return fmt.Errorf("%s can't be called with named arguments", name)
+	}
+
+	values, err := renderer.callArguments(call, component, variables)
+
+	if err != nil {
+		return err
+	}
+
+	return renderer.component(component, append([]reflect.Value{receiver}, values...))
+}
+
+// packageCall renders a call to a component in another package using the function
+// registered with Funcs, e.g. "ui.Button". Calls with named arguments use the
+// function rendering the component with props, e.g. "ui.ButtonWith".
+func (renderer *renderer) packageCall(qualified *pixy.Call, variables *scope) error {
+	name := qualified.Package + "." + qualified.Name
+	names, _ := qualified.NamedArguments()
+
+	if names != nil {
+		name += "With"
+	}
+
+	function, exists := renderer.template.globals[name]
+
+	if !exists {
+		return fmt.Errorf("undefined: %s", name)
+	}
+
+	var arguments []reflect.Value
+	var ellipsis bool
+	var err error
+
+	if names != nil {
+		arguments, err = renderer.props(qualified, function, variables)
+	} else {
+		arguments, ellipsis, err = renderer.positional(qualified, variables)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	results, err := call(function, arguments, ellipsis)
+
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("%s doesn't return any output", name)
+	}
+
+	if len(results) == 2 && results[1].Type() == errorType && !results[1].IsNil() {
+		return &failure{err: results[1].Interface().(error)}
+	}
+
+	renderer.write(raw(results[0]))
+	return nil
+}
+
+// props returns the props struct with the named arguments of a call
+// as the only argument of the function. The omitted arguments have their default values
+// if the constructor of the props is registered as well, e.g. "ui.NewButtonProps".
+func (renderer *renderer) props(qualified *pixy.Call, function reflect.Value, variables *scope) ([]reflect.Value, error) {
+	function = concrete(function)
+
+	if function.Kind() != reflect.Func || function.Type().NumIn() != 1 || function.Type().In(0).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s.%sWith doesn't accept props", qualified.Package, qualified.Name)
+	}
+
+	names, expressions := qualified.NamedArguments()
+	props := reflect.New(function.Type().In(0)).Elem()
+	constructor, exists := renderer.template.globals[qualified.Package+".New"+qualified.Name+"Props"]
+
+	if exists {
+		constructor = concrete(constructor)
+
+		if constructor.Kind() != reflect.Func || constructor.Type().NumIn() != 0 || constructor.Type().NumOut() != 1 || constructor.Type().Out(0) != props.Type() {
+			return nil, fmt.Errorf("%s.New%sProps doesn't return the props", qualified.Package, qualified.Name)
+		}
+
+		props.Set(constructor.Call(nil)[0])
+	}
+
+	for index, field := range names {
+		value, err := renderer.evaluate(expressions[index], variables)
+
+		if err != nil {
+			return nil, err
+		}
+
+		target := props.FieldByName(exported(field))
+
+		if !target.IsValid() {
+			return nil, fmt.Errorf("%s.%s has no parameter %s", qualified.Package, qualified.Name, field)
+		}
+
+		value = convert(value, target.Type().String())
+
+		if !value.IsValid() {
+			continue
+		}
+
+		if !value.Type().AssignableTo(target.Type()) {
+			return nil, fmt.Errorf("cannot use %s as %s in parameter %s", value.Type(), target.Type(), field)
+		}
+
+		target.Set(value)
+	}
+
+	return []reflect.Value{props}, nil
+}
+
+// positional returns the values of the positional arguments of a call
+// and whether the last argument is spread with ...
+func (renderer *renderer) positional(qualified *pixy.Call, variables *scope) ([]reflect.Value, bool, error) {
+	expression, err := renderer.template.parse(qualified.Name + "(" + qualified.Arguments + ")")
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	callExpression := expression.(*ast.CallExpr)
+	values := make([]reflect.Value, len(callExpression.Args))
+
+	for index, argument := range callExpression.Args {
+		values[index], err = renderer.expression(argument, variables)
+
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return values, callExpression.Ellipsis.IsValid(), nil
+}
+
+// dynamicCall renders a call to a component whose name is the first argument.
+func (renderer *renderer) dynamicCall(call *pixy.DynamicCall, variables *scope) error {
+	expression, err := renderer.template.parse("Dynamic(" + call.Arguments + ")")
+
+	if err != nil {
+		return err
+	}
+
+	callExpression := expression.(*ast.CallExpr)
+	var values []reflect.Value
+
+	for _, argument := range callExpression.Args {
+		value, err := renderer.expression(argument, variables)
+
+		if err != nil {
+			return err
+		}
+
+		values = append(values, value)
+	}
+
+	// Arguments passed as a slice
+	if callExpression.Ellipsis.IsValid() && len(values) > 0 {
+		spread := indirect(values[len(values)-1])
+		values = values[:len(values)-1]
+
+		for index := 0; spread.IsValid() && index < spread.Len(); index++ {
+			values = append(values, spread.Index(index))
+		}
+	}
+
+	if len(values) == 0 || concrete(values[0]).Kind() != reflect.String {
+		return fmt.Errorf("dynamic calls need the component name as the first argument")
+	}
+
+	name := concrete(values[0]).String()
+	component, exists := renderer.template.components[name]
+
+	// Like the registry, dynamic calls can't render methods
+	if !exists || component.receiver != nil {
+		return fmt.Errorf("unknown component %s", name)
+	}
+
+	// Like the registry, dynamic calls pass the variadic arguments as a slice
+	arguments, err := renderer.complete(component, values[1:], true)
+
+	if err != nil {
+		return err
+	}
+
+	return renderer.component(component, arguments)
+}
+
+// namedCall renders a call with named arguments.
+// Omitted arguments have their default value or the zero value of their type.
+func (renderer *renderer) namedCall(call *pixy.Call, component *component, variables *scope) error {
+	names, expressions := call.NamedArguments()
+	values := make([]reflect.Value, len(component.parameters))
+
+	for index, parameter := range component.parameters {
+		value, hasDefault := component.definition.Defaults[parameter.name]
+
+		if hasDefault {
+			var err error
+			values[index], err = renderer.evaluate(value, &scope{})
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		basicType, isBasic := basicTypes[parameter.typeName]
+
+		if isBasic {
+			values[index] = reflect.Zero(basicType)
+		}
+	}
+
+	for index, name := range names {
+		position := -1
+
+		for parameterIndex, parameter := range component.parameters {
+			if parameter.name == name {
+				position = parameterIndex
+			}
+		}
+
+		if position == -1 {
+			return fmt.Errorf("%s has no parameter %s", call.Name, name)
+		}
+
+		value, err := renderer.evaluate(expressions[index], variables)
+
+		if err != nil {
+			return err
+		}
+
+		values[position] = value
+	}
+
+	return renderer.component(component, values)
+}
+
+// fallible evaluates an expression returning a value and an error.
+func (renderer *renderer) fallible(expression string, variables *scope) (reflect.Value, error) {
+	parsed, err := renderer.template.parse(expression)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	call, isCall := parsed.(*ast.CallExpr)
+
+	if !isCall {
+		return reflect.Value{}, fmt.Errorf("%s doesn't return an error", expression)
+	}
+
+	results, err := renderer.callExpression(call, variables)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if len(results) != 2 || results[1].Type() != errorType {
+		return reflect.Value{}, fmt.Errorf("%s doesn't return a value and an error", expression)
+	}
+
+	if !results[1].IsNil() {
+		return reflect.Value{}, &failure{err: results[1].Interface().(error)}
+	}
+
+	return results[0], nil
+}
+
+// deferredBlock renders the placeholder of a deferred block and queues its content
+// until the end of the body element, like the generated code does.
+func (renderer *renderer) deferredBlock(deferred *pixy.Deferred, variables *scope) error {
+	output := renderer.output
+	buffer := strings.Builder{}
+	renderer.output = &buffer
+	err := renderer.children(deferred.Children, variables.child())
+	renderer.output = output
+
+	if err != nil {
+		return err
+	}
+
+	renderer.lastID++
+	id := strconv.Itoa(renderer.lastID)
+	renderer.deferred = append(renderer.deferred, &deferredFragment{id: id, output: buffer.String()})
+	renderer.write("<div id='pixy-deferred-" + id + "'>")
+	err = renderer.children(deferred.Placeholder, variables)
+	renderer.write("</div>")
+	return err
+}
+
+// filter writes the text of a filter block transformed by the filter.
+func (renderer *renderer) filter(filter *pixy.Filter) error {
+	transform, exists := renderer.template.filters[filter.Name]
+
+	if !exists {
+		transform, exists = pixy.BuiltinFilter(filter.Name)
+	}
+
+	if !exists {
+		return fmt.Errorf("unknown filter %s", filter.Name)
+	}
+
+	output, err := transform(filter.Text)
+
+	if err != nil {
+		return fmt.Errorf("filter %s: %v", filter.Name, err)
+	}
+
+	renderer.write(output)
+	return nil
+}
+
+// flushDeferred writes the content of the deferred blocks.
+func (renderer *renderer) flushDeferred() {
+	if len(renderer.deferred) == 0 {
+		return
+	}
+
+	renderer.flush()
+
+	for _, fragment := range renderer.deferred {
+		renderer.write("<template id='pixy-deferred-" + fragment.id + "-content'>")
+		renderer.write(fragment.output)
+		renderer.write("</template><script>(function(){var p=document.getElementById('pixy-deferred-" + fragment.id + "'),t=document.getElementById('pixy-deferred-" + fragment.id + "-content');p.replaceWith(t.content);t.remove()})()</script>")
+	}
+
+	renderer.deferred = nil
+}
+
+// flush sends the output written so far to the client if the writer supports it.
+func (renderer *renderer) flush() {
+	switch output := renderer.output.(type) {
+	case interface{ Flush() error }:
+		output.Flush()
+	case interface{ Flush() }:
+		output.Flush()
+	}
+}
+
+// writeValue writes a value with or without HTML escaping.
+func (renderer *renderer) writeValue(value reflect.Value, isRaw bool) {
+	if isRaw {
+		renderer.write(raw(value))
+		return
+	}
+
+	renderer.write(escape(value))
+}
+
+// write writes a string to the output.
+// The first error is kept and stops all further writes.
+func (renderer *renderer) write(s string) {
+	if renderer.err != nil {
+		return
+	}
+
+	_, renderer.err = io.WriteString(renderer.output, s)
+}
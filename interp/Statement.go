@@ -0,0 +1,362 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"github.com/aerogo/pixy"
+)
+
+// statement returns the parsed statement of a block.
+// The statement is parsed with an empty body.
+func (template *Template) statement(source string) (ast.Stmt, error) {
+	source = strings.TrimPrefix(source, "else ")
+	cached, exists := template.expressions.Load("statement:" + source)
+
+	if exists {
+		return cached.(ast.Stmt), nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc f() {\n"+source+" {\n}\n}", 0)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement %s", source)
+	}
+
+	body := file.Decls[0].(*ast.FuncDecl).Body.List
+
+	if len(body) != 1 {
+		return nil, fmt.Errorf("invalid statement %s", source)
+	}
+
+	template.expressions.Store("statement:"+source, body[0])
+	return body[0], nil
+}
+
+// ifBlock renders an if or else if block and tells whether its condition was true.
+func (renderer *renderer) ifBlock(block *pixy.Block, variables *scope) (bool, error) {
+	statement, err := renderer.template.statement(block.Statement)
+
+	if err != nil {
+		return false, err
+	}
+
+	ifStatement, isIf := statement.(*ast.IfStmt)
+
+	if !isIf {
+		return false, fmt.Errorf("invalid statement %s", block.Statement)
+	}
+
+	variables = variables.child()
+
+	if ifStatement.Init != nil {
+		err = renderer.execute(ifStatement.Init, variables)
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	condition, err := renderer.expression(ifStatement.Cond, variables)
+
+	if err != nil {
+		return false, err
+	}
+
+	if condition.Kind() != reflect.Bool {
+		return false, fmt.Errorf("non-boolean condition in %s", block.Statement)
+	}
+
+	if !condition.Bool() {
+		return false, nil
+	}
+
+	return true, renderer.children(block.Children, variables)
+}
+
+// forBlock renders a for loop.
+func (renderer *renderer) forBlock(block *pixy.Block, variables *scope) error {
+	statement, err := renderer.template.statement(block.Statement)
+
+	if err != nil {
+		return err
+	}
+
+	switch statement := statement.(type) {
+	case *ast.RangeStmt:
+		collection, err := renderer.expression(statement.X, variables)
+
+		if err != nil {
+			return err
+		}
+
+		return iterate(collection, false, func(key reflect.Value, value reflect.Value) error {
+			iteration := variables.child()
+
+			for _, assignment := range []struct {
+				target ast.Expr
+				value  reflect.Value
+			}{{statement.Key, key}, {statement.Value, value}} {
+				if assignment.target == nil {
+					continue
+				}
+
+				err := renderer.assign(assignment.target, assignment.value, iteration, statement.Tok == token.DEFINE)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			return renderer.children(block.Children, iteration)
+		})
+
+	case *ast.ForStmt:
+		variables = variables.child()
+
+		if statement.Init != nil {
+			err = renderer.execute(statement.Init, variables)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for {
+			if statement.Cond != nil {
+				condition, err := renderer.expression(statement.Cond, variables)
+
+				if err != nil {
+					return err
+				}
+
+				if condition.Kind() != reflect.Bool {
+					return fmt.Errorf("non-boolean condition in %s", block.Statement)
+				}
+
+				if !condition.Bool() {
+					return nil
+				}
+			}
+
+			err = renderer.children(block.Children, variables.child())
+
+			if err != nil {
+				return err
+			}
+
+			if statement.Post != nil {
+				err = renderer.execute(statement.Post, variables)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("unsupported statement %s", block.Statement)
+}
+
+// each renders the children for every element of a slice.
+func (renderer *renderer) each(each *pixy.Each, variables *scope) error {
+	collection, err := renderer.evaluate(each.Slice, variables)
+
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(each.Iterator)
+
+	return iterate(collection, each.Reversed, func(key reflect.Value, value reflect.Value) error {
+		iteration := variables.child()
+		iteration.variables[name] = value
+		return renderer.children(each.Children, iteration)
+	})
+}
+
+// iterate calls the function for every key and value of a slice, array, string or map.
+func iterate(collection reflect.Value, reversed bool, function func(key reflect.Value, value reflect.Value) error) error {
+	collection = indirect(collection)
+
+	switch collection.Kind() {
+	case reflect.Slice, reflect.Array:
+		length := collection.Len()
+
+		for index := 0; index < length; index++ {
+			if reversed {
+				index := length - 1 - index
+				err := function(reflect.ValueOf(index), collection.Index(index))
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			err := function(reflect.ValueOf(index), collection.Index(index))
+
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.String:
+		if reversed {
+			return iterate(reflect.ValueOf([]byte(collection.String())), true, function)
+		}
+
+		for index, character := range collection.String() {
+			err := function(reflect.ValueOf(index), reflect.ValueOf(character))
+
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iterator := collection.MapRange()
+
+		for iterator.Next() {
+			err := function(iterator.Key(), iterator.Value())
+
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Invalid:
+		return nil
+
+	default:
+		return fmt.Errorf("can't iterate over %s", collection.Type())
+	}
+
+	return nil
+}
+
+// execute executes a simple statement like an assignment or an increment.
+func (renderer *renderer) execute(statement ast.Stmt, variables *scope) error {
+	switch statement := statement.(type) {
+	case *ast.AssignStmt:
+		var values []reflect.Value
+
+		if len(statement.Rhs) == 1 && len(statement.Lhs) > 1 {
+			call, isCall := statement.Rhs[0].(*ast.CallExpr)
+
+			if !isCall {
+				return fmt.Errorf("unsupported assignment")
+			}
+
+			results, err := renderer.callExpression(call, variables)
+
+			if err != nil {
+				return err
+			}
+
+			values = results
+		} else {
+			for _, expression := range statement.Rhs {
+				value, err := renderer.expression(expression, variables)
+
+				if err != nil {
+					return err
+				}
+
+				values = append(values, value)
+			}
+		}
+
+		if len(values) != len(statement.Lhs) {
+			return fmt.Errorf("assignment mismatch: %d variables but %d values", len(statement.Lhs), len(values))
+		}
+
+		for index, target := range statement.Lhs {
+			value := values[index]
+
+			// Assignments like i += 2
+			if statement.Tok != token.ASSIGN && statement.Tok != token.DEFINE {
+				current, err := renderer.expression(target, variables)
+
+				if err != nil {
+					return err
+				}
+
+				value, err = binary(assignmentOperators[statement.Tok], current, value)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			err := renderer.assign(target, value, variables, statement.Tok == token.DEFINE)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case *ast.IncDecStmt:
+		current, err := renderer.expression(statement.X, variables)
+
+		if err != nil {
+			return err
+		}
+
+		operator := token.ADD
+
+		if statement.Tok == token.DEC {
+			operator = token.SUB
+		}
+
+		value, err := binary(operator, current, reflect.ValueOf(1))
+
+		if err != nil {
+			return err
+		}
+
+		return renderer.assign(statement.X, value, variables, false)
+	}
+
+	return fmt.Errorf("unsupported statement %T", statement)
+}
+
+// assign assigns a value to a variable. New variables are declared in the given scope.
+func (renderer *renderer) assign(target ast.Expr, value reflect.Value, variables *scope, declare bool) error {
+	identifier, isIdentifier := target.(*ast.Ident)
+
+	if !isIdentifier {
+		return fmt.Errorf("unsupported assignment target")
+	}
+
+	if identifier.Name == "_" {
+		return nil
+	}
+
+	if declare {
+		variables.variables[identifier.Name] = value
+		return nil
+	}
+
+	if !variables.set(identifier.Name, value) {
+		return fmt.Errorf("undefined: %s", identifier.Name)
+	}
+
+	return nil
+}
+
+// assignmentOperators maps assignment operators to their binary operators.
+var assignmentOperators = map[token.Token]token.Token{
+	token.ADD_ASSIGN: token.ADD,
+	token.SUB_ASSIGN: token.SUB,
+	token.MUL_ASSIGN: token.MUL,
+	token.QUO_ASSIGN: token.QUO,
+	token.REM_ASSIGN: token.REM,
+}
@@ -0,0 +1,357 @@
+package interp
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aerogo/pixy"
+)
+
+// Template is a set of Pixy components that are rendered at runtime
+// instead of being compiled to Go code. Templates can be parsed again
+// while the program is running, e.g. by a development server.
+type Template struct {
+	mutex       sync.RWMutex
+	components  map[string]*component
+	globals     map[string]reflect.Value
+	filters     map[string]func(string) (string, error)
+	transforms  []pixy.Transform
+	macros      map[string]*pixy.Macro
+	expressions sync.Map
+}
+
+// component is a parsed component definition together with its parameters.
+// Components defined as methods have a receiver.
+type component struct {
+	definition *pixy.Definition
+	receiver   *parameter
+	parameters []*parameter
+}
+
+// name returns the name of the component. Methods are prefixed with the name of the receiver type.
+func (component *component) name() string {
+	if component.receiver == nil {
+		return component.definition.Name
+	}
+
+	typeName := strings.TrimPrefix(component.receiver.typeName, "*")
+
+	if bracket := strings.Index(typeName, "["); bracket != -1 {
+		typeName = typeName[:bracket]
+	}
+
+	return typeName + "." + component.definition.Name
+}
+
+// variadic tells whether the last parameter of the component is variadic.
+func (component *component) variadic() bool {
+	parameters := component.parameters
+	return len(parameters) > 0 && strings.HasPrefix(parameters[len(parameters)-1].typeName, "...")
+}
+
+// parameter is a component parameter with its type.
+type parameter struct {
+	name     string
+	typeName string
+}
+
+// New creates a template without any components.
+func New() *Template {
+	return &Template{
+		components: map[string]*component{},
+		globals:    map[string]reflect.Value{},
+		filters:    map[string]func(string) (string, error){},
+		macros:     map[string]*pixy.Macro{},
+	}
+}
+
+// Funcs adds functions and values that the components can refer to by name.
+// Functions from other packages can be added with their qualified name, e.g. "strings.ToUpper".
+func (template *Template) Funcs(globals map[string]interface{}) *Template {
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+
+	for name, value := range globals {
+		template.globals[name] = reflect.ValueOf(value)
+	}
+
+	return template
+}
+
+// RegisterFilter adds a filter that transforms the text of :name blocks.
+// Filters with the name of a built-in filter replace it.
+func (template *Template) RegisterFilter(name string, filter func(string) (string, error)) *Template {
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+	template.filters[name] = filter
+	return template
+}
+
+// Macros adds macros that replace elements by component calls like the Macros option of the compiler.
+// They only apply to the components parsed afterwards.
+func (template *Template) Macros(macros map[string]*pixy.Macro) *Template {
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+
+	for tag, macro := range macros {
+		template.macros[tag] = macro
+	}
+
+	return template
+}
+
+// Transform adds transforms that change the parsed components before they are added.
+// They only apply to the components parsed afterwards.
+func (template *Template) Transform(transforms ...pixy.Transform) *Template {
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+	template.transforms = append(template.transforms, transforms...)
+	return template
+}
+
+// Parse adds the components of a Pixy template.
+// Components with the same name as an existing component replace it.
+// Components defined as methods are named after the receiver type, e.g. "Post.Card".
+// Included files are relative to the working directory.
+func (template *Template) Parse(reader io.Reader) error {
+	definitions, err := pixy.Parse(reader)
+
+	if err != nil {
+		return err
+	}
+
+	return template.add(definitions)
+}
+
+// ParseString adds the components of a Pixy template given as a string.
+func (template *Template) ParseString(src string) error {
+	return template.Parse(strings.NewReader(src))
+}
+
+// ParseFile adds the components of a Pixy template read from a file.
+// Included files are relative to the directory of the template.
+func (template *Template) ParseFile(path string) error {
+	definitions, err := pixy.ParseFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	return template.add(definitions)
+}
+
+// add adds the components of the parsed definitions.
+func (template *Template) add(definitions []*pixy.Definition) error {
+	template.mutex.RLock()
+	transforms := template.transforms
+	err := pixy.ExpandMacros(definitions, template.macros)
+	template.mutex.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range definitions {
+		err := definition.Transform(transforms...)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	components := make([]*component, 0, len(definitions))
+
+	for _, definition := range definitions {
+		parameters, err := parseParameters(definition.Parameters)
+
+		if err != nil {
+			return fmt.Errorf("component %s: %v", definition.Name, err)
+		}
+
+		var receiver *parameter
+
+		if definition.Receiver != "" {
+			receivers, err := parseParameters(definition.Receiver)
+
+			if err != nil || len(receivers) != 1 {
+				return fmt.Errorf("component %s: invalid receiver %s", definition.Name, definition.Receiver)
+			}
+
+			receiver = receivers[0]
+		}
+
+		components = append(components, &component{
+			definition: definition,
+			receiver:   receiver,
+			parameters: parameters,
+		})
+	}
+
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+
+	for _, component := range components {
+		template.components[component.name()] = component
+	}
+
+	return nil
+}
+
+// Render renders a component and returns the output.
+// The parameters are a map[string]interface{} or a struct
+// with a field for each parameter of the component.
+func (template *Template) Render(name string, parameters interface{}) (string, error) {
+	output := strings.Builder{}
+	err := template.Execute(&output, name, parameters)
+
+	if err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+// Execute renders a component to the writer.
+// The parameters are a map[string]interface{} or a struct
+// with a field for each parameter of the component.
+func (template *Template) Execute(writer io.Writer, name string, parameters interface{}) error {
+	template.mutex.RLock()
+	defer template.mutex.RUnlock()
+
+	component, exists := template.components[name]
+
+	if !exists {
+		return fmt.Errorf("unknown component %s", name)
+	}
+
+	renderer := &renderer{
+		template: template,
+		output:   writer,
+	}
+
+	arguments, err := renderer.arguments(component, parameters)
+
+	if err != nil {
+		return err
+	}
+
+	err = renderer.component(component, arguments)
+
+	if failure, isFailure := err.(*failure); isFailure {
+		return failure.err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Components without a body element write the deferred content at the end
+	renderer.flushDeferred()
+	return renderer.err
+}
+
+// arguments returns the values of the parameters in the order of the component parameters.
+// The receiver of a method is the first value. Missing parameters with a default value use the default value.
+func (renderer *renderer) arguments(component *component, parameters interface{}) ([]reflect.Value, error) {
+	all := component.parameters
+
+	if component.receiver != nil {
+		all = append([]*parameter{component.receiver}, all...)
+	}
+
+	arguments := make([]reflect.Value, len(all))
+	value := reflect.ValueOf(parameters)
+
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	for index, parameter := range all {
+		var argument reflect.Value
+
+		switch value.Kind() {
+		case reflect.Map:
+			argument = value.MapIndex(reflect.ValueOf(parameter.name))
+
+			// Nil values are passed on as nil
+			if argument.IsValid() {
+				arguments[index] = concrete(argument)
+				continue
+			}
+
+		case reflect.Struct:
+			argument = value.FieldByName(parameter.name)
+
+			if !argument.IsValid() {
+				argument = value.FieldByName(exported(parameter.name))
+			}
+
+		default:
+			return nil, fmt.Errorf("component %s: parameters must be a map or a struct", component.definition.Name)
+		}
+
+		if !argument.IsValid() {
+			defaultValue, hasDefault := component.definition.Defaults[parameter.name]
+
+			if !hasDefault {
+				return nil, fmt.Errorf("component %s: missing parameter %s", component.definition.Name, parameter.name)
+			}
+
+			var err error
+			argument, err = renderer.evaluate(defaultValue, &scope{})
+
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %v", component.definition.Name, err)
+			}
+		}
+
+		arguments[index] = argument
+	}
+
+	return arguments, nil
+}
+
+// parseParameters returns the parameters of a Go parameter list.
+func parseParameters(parameters string) ([]*parameter, error) {
+	if parameters == "" {
+		return nil, nil
+	}
+
+	source := "package p\nfunc f(" + parameters + ")"
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+
+	if err != nil {
+		return nil, errors.New("invalid parameters " + parameters)
+	}
+
+	var result []*parameter
+	function := file.Decls[0].(*ast.FuncDecl)
+
+	for _, field := range function.Type.Params.List {
+		typeName := source[field.Type.Pos()-1 : field.Type.End()-1]
+
+		for _, name := range field.Names {
+			result = append(result, &parameter{
+				name:     name.Name,
+				typeName: typeName,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// exported returns the name with an uppercase first letter.
+func exported(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
@@ -0,0 +1,175 @@
+package interp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aerogo/pixy"
+	"github.com/aerogo/pixy/interp"
+	"github.com/akyoto/assert"
+)
+
+type user struct {
+	Name    string
+	Age     int
+	Friends []*user
+}
+
+func (u *user) Greeting(prefix string) string {
+	return prefix + " " + u.Name
+}
+
+var errNotFound = errors.New("not found")
+
+const src = `
+component Profile(user *user)
+	h1(title=strings.ToUpper(user.Name))= user.Greeting("Hi")
+	if user.Age >= 18 && len(user.Friends) > 0
+		p Adult with friends
+	else if user.Age >= 18
+		p Adult
+	else
+		p= "Minor, " + strconv.Itoa(18 - user.Age) + " years to go"
+	ul
+		each friend in user.Friends reversed
+			Friend(friend.Name, friend.Age * 2)
+	for i := 0; i < 3; i += 2
+		span= i
+
+component Friend(name string, score float64)
+	li(data-score=score / 4)= name
+
+component Lookup(id int) error
+	p?= find(id)
+`
+
+func newTemplate(t *testing.T) *interp.Template {
+	template := interp.New().Funcs(map[string]interface{}{
+		"strings.ToUpper": strings.ToUpper,
+		"strconv.Itoa": func(i int) string {
+			return strings.Repeat("I", i)
+		},
+		"find": func(id int) (string, error) {
+			if id != 1 {
+				return "", errNotFound
+			}
+
+			return "<found>", nil
+		},
+	})
+
+	assert.Nil(t, template.ParseString(src))
+	return template
+}
+
+func TestRender(t *testing.T) {
+	template := newTemplate(t)
+
+	output, err := template.Render("Profile", map[string]interface{}{
+		"user": &user{Name: "Eve", Age: 20, Friends: []*user{{Name: "Bob", Age: 3}, {Name: "Amy", Age: 5}}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<h1 title='EVE'>Hi Eve</h1><p>Adult with friends</p><ul><li data-score='2.5'>Amy</li><li data-score='1.5'>Bob</li></ul><span>0</span><span>2</span>")
+
+	output, err = template.Render("Profile", map[string]interface{}{"user": &user{Name: "Tim", Age: 16}})
+	assert.Nil(t, err)
+	assert.Contains(t, output, "<p>Minor, II years to go</p>")
+}
+
+func TestRenderStruct(t *testing.T) {
+	template := newTemplate(t)
+
+	output, err := template.Render("Friend", struct {
+		Name  string
+		Score int
+	}{"<Bob>", 6})
+
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<li data-score='1.5'>&lt;Bob&gt;</li>")
+}
+
+func TestRenderError(t *testing.T) {
+	template := newTemplate(t)
+
+	output, err := template.Render("Lookup", map[string]interface{}{"id": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<p>&lt;found&gt;</p>")
+
+	_, err = template.Render("Lookup", map[string]interface{}{"id": 2})
+	assert.Equal(t, err, errNotFound)
+
+	_, err = template.Render("Unknown", nil)
+	assert.NotNil(t, err)
+
+	_, err = template.Render("Friend", map[string]interface{}{"name": "Bob"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "missing parameter score")
+
+	_, err = template.Render("Friend", map[string]interface{}{"name": "Bob", "score": "high"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "component Friend")
+}
+
+func TestReload(t *testing.T) {
+	template := newTemplate(t)
+	assert.Nil(t, template.ParseString("component Friend(name string, score float64)\n\tli= name"))
+
+	output, err := template.Render("Friend", map[string]interface{}{"name": "Bob", "score": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<li>Bob</li>")
+}
+
+func TestExecute(t *testing.T) {
+	template := newTemplate(t)
+	output := &strings.Builder{}
+	assert.Nil(t, template.Execute(output, "Friend", map[string]interface{}{"name": "Bob", "score": 4}))
+	assert.Equal(t, output.String(), "<li data-score='1'>Bob</li>")
+}
+
+func TestGoBlocks(t *testing.T) {
+	template := interp.New().Funcs(map[string]interface{}{
+		"double": func(x int) int { return x * 2 },
+	})
+
+	assert.Nil(t, template.ParseString("go\n\tfunc double(x int) int {\n\t\treturn x * 2\n\t}\n\ncomponent Double(x int)\n\tspan= double(x)"))
+
+	output, err := template.Render("Double", map[string]interface{}{"x": 21})
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<span>42</span>")
+}
+
+func TestUnknownFilter(t *testing.T) {
+	template := interp.New()
+	assert.Nil(t, template.ParseString("component Page\n\t:sass\n\t\tbody { }"))
+
+	_, err := template.Render("Page", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown filter sass")
+}
+
+func TestTransform(t *testing.T) {
+	template := interp.New().Transform(func(definition *pixy.Definition) error {
+		return pixy.Walk(definition.Children, func(node pixy.Node) error {
+			if element, isElement := node.(*pixy.Element); isElement && element.Name == "img" {
+				element.SetAttribute("loading", `"lazy"`)
+			}
+
+			return nil
+		})
+	})
+
+	assert.Nil(t, template.ParseString("component Image(src string)\n\timg(src=src)"))
+
+	output, err := template.Render("Image", map[string]interface{}{"src": "/a.png"})
+	assert.Nil(t, err)
+	assert.Equal(t, output, "<img src='/a.png' loading='lazy'>")
+
+	err = interp.New().Transform(func(definition *pixy.Definition) error {
+		return definition.Children[0].(*pixy.Element).Position.Errorf("not allowed")
+	}).ParseString("component Page\n\tp Text")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "2:2: not allowed")
+}
@@ -0,0 +1,592 @@
+package interp
+
+import (
+	"fmt"
+	"go/token"
+	"html"
+	"reflect"
+	"strconv"
+)
+
+// errorType is the type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// basicTypes maps the names of the basic types to their types.
+var basicTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+}
+
+// concrete returns the value stored in an interface.
+// Nil interfaces are returned as invalid values.
+func concrete(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// indirect returns the value a pointer points to.
+func indirect(value reflect.Value) reflect.Value {
+	value = concrete(value)
+
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// convert converts a value to the basic type with the given name.
+// Values of other types are returned unchanged.
+func convert(value reflect.Value, typeName string) reflect.Value {
+	target, isBasic := basicTypes[typeName]
+	value = concrete(value)
+
+	if !isBasic || !value.IsValid() || value.Type() == target || !value.Type().ConvertibleTo(target) {
+		return value
+	}
+
+	if (isNumber(value) && target.Kind() != reflect.String) || (value.Kind() == reflect.String && target.Kind() == reflect.String) {
+		return value.Convert(target)
+	}
+
+	return value
+}
+
+// collect returns the arguments of a variadic parameter as a slice.
+// Elements of types other than the basic types are collected in a []interface{}.
+func collect(arguments []reflect.Value, elementType string) (reflect.Value, error) {
+	target, isBasic := basicTypes[elementType]
+
+	if !isBasic {
+		target = reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(target), len(arguments), len(arguments))
+
+	for index, argument := range arguments {
+		argument = convert(argument, elementType)
+
+		if !argument.IsValid() {
+			continue
+		}
+
+		if !argument.Type().AssignableTo(target) {
+			return reflect.Value{}, fmt.Errorf("cannot use %s as %s", argument.Type(), elementType)
+		}
+
+		slice.Index(index).Set(argument)
+	}
+
+	return slice, nil
+}
+
+// member returns the method or field with the given name.
+func member(value reflect.Value, name string) (reflect.Value, error) {
+	value = concrete(value)
+
+	if !value.IsValid() {
+		return reflect.Value{}, fmt.Errorf("nil value has no field or method %s", name)
+	}
+
+	method := value.MethodByName(name)
+
+	if method.IsValid() {
+		return method, nil
+	}
+
+	// Methods with a pointer receiver
+	if value.Kind() != reflect.Ptr {
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		method = pointer.MethodByName(name)
+
+		if method.IsValid() {
+			return method, nil
+		}
+	}
+
+	structValue := indirect(value)
+
+	if structValue.Kind() == reflect.Struct {
+		field := structValue.FieldByName(name)
+
+		if field.IsValid() {
+			if !field.CanInterface() {
+				return reflect.Value{}, fmt.Errorf("unexported field %s of %s", name, structValue.Type())
+			}
+
+			return field, nil
+		}
+	}
+
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return reflect.Value{}, fmt.Errorf("nil pointer dereference accessing %s", name)
+	}
+
+	return reflect.Value{}, fmt.Errorf("%s has no field or method %s", value.Type(), name)
+}
+
+// index returns the element of a slice, array, string or map.
+func index(collection reflect.Value, key reflect.Value) (reflect.Value, error) {
+	collection = indirect(collection)
+
+	switch collection.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !isInteger(key) {
+			return reflect.Value{}, fmt.Errorf("non-integer index")
+		}
+
+		position := int(toInt(key))
+
+		if position < 0 || position >= collection.Len() {
+			return reflect.Value{}, fmt.Errorf("index out of range [%d] with length %d", position, collection.Len())
+		}
+
+		return collection.Index(position), nil
+
+	case reflect.Map:
+		key = convert(key, collection.Type().Key().String())
+
+		if !key.IsValid() {
+			key = reflect.Zero(collection.Type().Key())
+		}
+
+		if !key.Type().AssignableTo(collection.Type().Key()) {
+			return reflect.Value{}, fmt.Errorf("cannot use %s as %s key", key.Type(), collection.Type().Key())
+		}
+
+		element := collection.MapIndex(key)
+
+		if !element.IsValid() {
+			return reflect.Zero(collection.Type().Elem()), nil
+		}
+
+		return element, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("can't index %s", typeName(collection))
+}
+
+// call calls a function with the arguments converted to the parameter types.
+func call(function reflect.Value, arguments []reflect.Value, ellipsis bool) ([]reflect.Value, error) {
+	function = concrete(function)
+
+	if function.Kind() != reflect.Func || function.IsNil() {
+		return nil, fmt.Errorf("can't call %s", typeName(function))
+	}
+
+	functionType := function.Type()
+	parameterCount := functionType.NumIn()
+
+	if functionType.IsVariadic() && !ellipsis {
+		if len(arguments) < parameterCount-1 {
+			return nil, fmt.Errorf("not enough arguments in call to %s", functionType)
+		}
+	} else if len(arguments) != parameterCount {
+		return nil, fmt.Errorf("wrong number of arguments in call to %s", functionType)
+	}
+
+	for index, argument := range arguments {
+		var parameterType reflect.Type
+
+		if functionType.IsVariadic() && !ellipsis && index >= parameterCount-1 {
+			parameterType = functionType.In(parameterCount - 1).Elem()
+		} else {
+			parameterType = functionType.In(index)
+		}
+
+		argument = convert(argument, parameterType.String())
+
+		if !argument.IsValid() {
+			arguments[index] = reflect.Zero(parameterType)
+			continue
+		}
+
+		if !argument.Type().AssignableTo(parameterType) {
+			return nil, fmt.Errorf("cannot use %s as %s in call to %s", argument.Type(), parameterType, functionType)
+		}
+
+		arguments[index] = argument
+	}
+
+	if ellipsis {
+		return function.CallSlice(arguments), nil
+	}
+
+	return function.Call(arguments), nil
+}
+
+// builtin calls a builtin function or a conversion to a basic type.
+// It tells whether the name refers to a builtin.
+func builtin(name string, arguments []reflect.Value) (reflect.Value, bool, error) {
+	if name == "len" || name == "cap" {
+		if len(arguments) != 1 {
+			return reflect.Value{}, true, fmt.Errorf("wrong number of arguments for %s", name)
+		}
+
+		value := indirect(arguments[0])
+
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Chan:
+			if name == "cap" {
+				return reflect.ValueOf(value.Cap()), true, nil
+			}
+
+			return reflect.ValueOf(value.Len()), true, nil
+
+		case reflect.String, reflect.Map:
+			if name == "len" {
+				return reflect.ValueOf(value.Len()), true, nil
+			}
+		}
+
+		return reflect.Value{}, true, fmt.Errorf("invalid argument %s for %s", typeName(value), name)
+	}
+
+	target, isBasic := basicTypes[name]
+
+	if !isBasic {
+		return reflect.Value{}, false, nil
+	}
+
+	if len(arguments) != 1 {
+		return reflect.Value{}, true, fmt.Errorf("wrong number of arguments for conversion to %s", name)
+	}
+
+	value := concrete(arguments[0])
+
+	if !value.IsValid() || !value.Type().ConvertibleTo(target) {
+		return reflect.Value{}, true, fmt.Errorf("cannot convert %s to %s", typeName(value), name)
+	}
+
+	return value.Convert(target), true, nil
+}
+
+// unary returns the result of a unary operation.
+func unary(operator token.Token, operand reflect.Value) (reflect.Value, error) {
+	if operator == token.AND {
+		if operand.CanAddr() {
+			return operand.Addr(), nil
+		}
+
+		pointer := reflect.New(operand.Type())
+		pointer.Elem().Set(operand)
+		return pointer, nil
+	}
+
+	operand = concrete(operand)
+
+	switch {
+	case operator == token.NOT && operand.Kind() == reflect.Bool:
+		return reflect.ValueOf(!operand.Bool()), nil
+
+	case operator == token.ADD && isNumber(operand):
+		return operand, nil
+
+	case operator == token.SUB && isInteger(operand):
+		return reflect.ValueOf(-toInt(operand)).Convert(operand.Type()), nil
+
+	case operator == token.SUB && isFloat(operand):
+		return reflect.ValueOf(-operand.Float()).Convert(operand.Type()), nil
+
+	case operator == token.XOR && isInteger(operand):
+		return reflect.ValueOf(^toInt(operand)).Convert(operand.Type()), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on %s", operator, typeName(operand))
+}
+
+// binary returns the result of a binary operation.
+func binary(operator token.Token, left reflect.Value, right reflect.Value) (reflect.Value, error) {
+	left = concrete(left)
+	right = concrete(right)
+
+	switch {
+	case !left.IsValid() || !right.IsValid():
+		if operator == token.EQL || operator == token.NEQ {
+			return reflect.ValueOf(isNil(left) == isNil(right) == (operator == token.EQL)), nil
+		}
+
+	case left.Kind() == reflect.String && right.Kind() == reflect.String:
+		return stringOperation(operator, left, right)
+
+	case isNumber(left) && isNumber(right):
+		if isFloat(left) || isFloat(right) {
+			return floatOperation(operator, left, right)
+		}
+
+		return integerOperation(operator, left, right)
+
+	case left.Kind() == reflect.Bool && right.Kind() == reflect.Bool:
+		switch operator {
+		case token.EQL:
+			return reflect.ValueOf(left.Bool() == right.Bool()), nil
+
+		case token.LAND:
+			return reflect.ValueOf(left.Bool() && right.Bool()), nil
+
+		case token.NEQ:
+			return reflect.ValueOf(left.Bool() != right.Bool()), nil
+
+		case token.LOR:
+			return reflect.ValueOf(left.Bool() || right.Bool()), nil
+		}
+
+	case operator == token.EQL || operator == token.NEQ:
+		if left.Type().Comparable() && right.Type().Comparable() {
+			return reflect.ValueOf((left.Interface() == right.Interface()) == (operator == token.EQL)), nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on %s and %s", operator, typeName(left), typeName(right))
+}
+
+// stringOperation returns the result of an operation on two strings.
+func stringOperation(operator token.Token, left reflect.Value, right reflect.Value) (reflect.Value, error) {
+	a := left.String()
+	b := right.String()
+
+	switch operator {
+	case token.ADD:
+		return reflect.ValueOf(a + b).Convert(left.Type()), nil
+	case token.EQL:
+		return reflect.ValueOf(a == b), nil
+	case token.NEQ:
+		return reflect.ValueOf(a != b), nil
+	case token.LSS:
+		return reflect.ValueOf(a < b), nil
+	case token.LEQ:
+		return reflect.ValueOf(a <= b), nil
+	case token.GTR:
+		return reflect.ValueOf(a > b), nil
+	case token.GEQ:
+		return reflect.ValueOf(a >= b), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on strings", operator)
+}
+
+// integerOperation returns the result of an operation on two integers.
+// Arithmetic results have the type of the left operand.
+func integerOperation(operator token.Token, left reflect.Value, right reflect.Value) (reflect.Value, error) {
+	a := toInt(left)
+	b := toInt(right)
+	var result int64
+
+	switch operator {
+	case token.ADD:
+		result = a + b
+	case token.SUB:
+		result = a - b
+	case token.MUL:
+		result = a * b
+	case token.QUO, token.REM:
+		if b == 0 {
+			return reflect.Value{}, fmt.Errorf("integer divide by zero")
+		}
+
+		if operator == token.QUO {
+			result = a / b
+		} else {
+			result = a % b
+		}
+	case token.AND:
+		result = a & b
+	case token.OR:
+		result = a | b
+	case token.XOR:
+		result = a ^ b
+	case token.SHL:
+		result = a << uint64(b)
+	case token.SHR:
+		result = a >> uint64(b)
+	default:
+		return compare(operator, float64(a-b))
+	}
+
+	return reflect.ValueOf(result).Convert(left.Type()), nil
+}
+
+// floatOperation returns the result of an operation on two numbers of which one is a float.
+// Arithmetic results have the float type of the operands.
+func floatOperation(operator token.Token, left reflect.Value, right reflect.Value) (reflect.Value, error) {
+	a := toFloat(left)
+	b := toFloat(right)
+	resultType := left.Type()
+
+	if !isFloat(left) {
+		resultType = right.Type()
+	}
+
+	switch operator {
+	case token.ADD:
+		return reflect.ValueOf(a + b).Convert(resultType), nil
+	case token.SUB:
+		return reflect.ValueOf(a - b).Convert(resultType), nil
+	case token.MUL:
+		return reflect.ValueOf(a * b).Convert(resultType), nil
+	case token.QUO:
+		return reflect.ValueOf(a / b).Convert(resultType), nil
+	}
+
+	return compare(operator, a-b)
+}
+
+// compare returns the result of a comparison given the difference of the operands.
+func compare(operator token.Token, difference float64) (reflect.Value, error) {
+	switch operator {
+	case token.EQL:
+		return reflect.ValueOf(difference == 0), nil
+	case token.NEQ:
+		return reflect.ValueOf(difference != 0), nil
+	case token.LSS:
+		return reflect.ValueOf(difference < 0), nil
+	case token.LEQ:
+		return reflect.ValueOf(difference <= 0), nil
+	case token.GTR:
+		return reflect.ValueOf(difference > 0), nil
+	case token.GEQ:
+		return reflect.ValueOf(difference >= 0), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on numbers", operator)
+}
+
+// isNil tells whether a value is nil.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return value.IsNil()
+	}
+
+	return false
+}
+
+// isInteger tells whether a value is a signed or unsigned integer.
+func isInteger(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// isFloat tells whether a value is a floating-point number.
+func isFloat(value reflect.Value) bool {
+	return value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64
+}
+
+// isNumber tells whether a value is an integer or a floating-point number.
+func isNumber(value reflect.Value) bool {
+	return isInteger(value) || isFloat(value)
+}
+
+// toInt returns an integer value as an int64.
+func toInt(value reflect.Value) int64 {
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(value.Uint())
+	}
+
+	return value.Int()
+}
+
+// toFloat returns a number as a float64.
+func toFloat(value reflect.Value) float64 {
+	if isFloat(value) {
+		return value.Float()
+	}
+
+	return float64(toInt(value))
+}
+
+// typeName returns the name of the type of a value for error messages.
+func typeName(value reflect.Value) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+
+	return value.Type().String()
+}
+
+// format returns the text representation of a value
+// the same way as the writeEscaped function of the generated code.
+func format(value reflect.Value) string {
+	if !value.IsValid() {
+		return fmt.Sprint(nil)
+	}
+
+	switch value := value.Interface().(type) {
+	case string:
+		return value
+	case int:
+		return strconv.FormatInt(int64(value), 10)
+	case int8:
+		return strconv.FormatInt(int64(value), 10)
+	case int16:
+		return strconv.FormatInt(int64(value), 10)
+	case int32:
+		return strconv.FormatInt(int64(value), 10)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case uint:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// escape returns the HTML-escaped text representation of a value.
+func escape(value reflect.Value) string {
+	return html.EscapeString(format(value))
+}
+
+// raw returns the text representation of a value that is written without escaping.
+func raw(value reflect.Value) string {
+	value = concrete(value)
+
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+
+	return format(value)
+}